@@ -4,11 +4,25 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Strategies for logins beyond the concurrent session limit
+const (
+	SessionLimitReject      = "reject"
+	SessionLimitEvictOldest = "evict_oldest"
+)
+
+// How registration asks users to verify their email address
+const (
+	EmailVerificationCode = "code"
+	EmailVerificationLink = "link"
+	EmailVerificationBoth = "both"
+)
+
 type Config struct {
 	Database struct {
 		Host     string
@@ -47,10 +61,80 @@ type Config struct {
 		ExpirationMinutes int
 		MaxAttempts       int
 	}
+	Registration struct {
+		InviteOnly           bool
+		InvitationExpiration time.Duration
+		VerificationMode     string
+		VerificationLinkTTL  time.Duration
+		NormalizeGmailDots   bool
+		NormalizeGmailPlus   bool
+		AllowedDomains       []string
+		BlockedDomains       []string
+		BlockDisposable      bool
+		DisposableDomainList string
+		UsernameCheckLimit   int
+		UsernameCheckWindow  time.Duration
+	}
+	Account struct {
+		DeletionGracePeriod time.Duration
+		PurgeInterval       time.Duration
+	}
+	Sessions struct {
+		MaxConcurrent int
+		LimitStrategy string
+	}
+	Cookie struct {
+		Enabled  bool
+		Domain   string
+		Secure   bool
+		SameSite string
+	}
+	Email struct {
+		Provider      string
+		From          string
+		FilePath      string
+		TemplateDir   string
+		DefaultLocale string
+		Workers       int
+		PollInterval  time.Duration
+		BatchSize     int
+		MaxAttempts   int
+	}
+	Notify struct {
+		Provider      string
+		From          string
+		WebhookURL    string
+		WebhookSecret string
+		TwilioSID     string
+		TwilioToken   string
+		OTPChannel    string
+
+		// Bounds on verification codes sent to a user or phone number
+		VerificationCooldown   time.Duration
+		VerificationDailyLimit int
+	}
+	Risk struct {
+		Enabled          bool
+		GeoIPDatabase    string
+		IPReputationList string
+		StepUpThreshold  int
+		MaxTravelSpeed   float64
+	}
+	APIKeys struct {
+		DefaultExpiration time.Duration
+	}
+	Authz struct {
+		PolicyDir        string
+		DecisionCacheTTL time.Duration
+	}
 	App struct {
 		Environment string
 		Port        string
 		GinMode     string
+		BaseURL     string
+		// DevTools enables the /dev routes and the mailbox, and is only set
+		// when APP_ENV is development or test
+		DevTools bool
 	}
 }
 
@@ -98,10 +182,90 @@ func LoadConfig() (*Config, error) {
 	config.OTP.ExpirationMinutes = getEnvAsInt("OTP_EXPIRATION_MINUTES", 5)
 	config.OTP.MaxAttempts = getEnvAsInt("MAX_OTP_ATTEMPTS", 3)
 
+	// Registration settings
+	config.Registration.InviteOnly = getEnvAsBool("REGISTRATION_INVITE_ONLY", false)
+	config.Registration.InvitationExpiration = time.Duration(getEnvAsInt("INVITATION_EXPIRATION_HOURS", 72)) * time.Hour
+	config.Registration.VerificationMode = strings.ToLower(getEnv("EMAIL_VERIFICATION_MODE", EmailVerificationCode))
+	config.Registration.VerificationLinkTTL = time.Duration(getEnvAsInt("EMAIL_VERIFICATION_LINK_HOURS", 24)) * time.Hour
+	config.Registration.NormalizeGmailDots = getEnvAsBool("EMAIL_NORMALIZE_GMAIL_DOTS", true)
+	config.Registration.NormalizeGmailPlus = getEnvAsBool("EMAIL_NORMALIZE_GMAIL_PLUS", true)
+	config.Registration.AllowedDomains = getEnvAsList("REGISTRATION_ALLOWED_DOMAINS")
+	config.Registration.BlockedDomains = getEnvAsList("REGISTRATION_BLOCKED_DOMAINS")
+	config.Registration.BlockDisposable = getEnvAsBool("REGISTRATION_BLOCK_DISPOSABLE", true)
+	config.Registration.DisposableDomainList = getEnv("DISPOSABLE_DOMAINS_PATH", "")
+	config.Registration.UsernameCheckLimit = getEnvAsInt("USERNAME_CHECK_LIMIT", 30)
+	config.Registration.UsernameCheckWindow = time.Duration(getEnvAsInt("USERNAME_CHECK_WINDOW_SECONDS", 60)) * time.Second
+
+	// Account lifecycle settings
+	config.Account.DeletionGracePeriod = time.Duration(getEnvAsInt("ACCOUNT_DELETION_GRACE_DAYS", 30)) * 24 * time.Hour
+	config.Account.PurgeInterval = time.Duration(getEnvAsInt("ACCOUNT_PURGE_INTERVAL_MINUTES", 60)) * time.Minute
+
+	// Session settings
+	config.Sessions.MaxConcurrent = getEnvAsInt("MAX_CONCURRENT_SESSIONS", 0)
+	config.Sessions.LimitStrategy = getEnv("SESSION_LIMIT_STRATEGY", SessionLimitEvictOldest)
+
+	// Cookie session mode for browser clients
+	config.Cookie.Enabled = getEnvAsBool("COOKIE_SESSIONS_ENABLED", false)
+	config.Cookie.Domain = getEnv("COOKIE_DOMAIN", "")
+	config.Cookie.Secure = getEnvAsBool("COOKIE_SECURE", true)
+	config.Cookie.SameSite = strings.ToLower(getEnv("COOKIE_SAMESITE", "lax"))
+
+	// Email delivery. Outside production mail is only logged unless a
+	// provider is chosen explicitly.
+	defaultEmailProvider := "log"
+	if os.Getenv("APP_ENV") == "production" {
+		defaultEmailProvider = "smtp"
+	}
+	config.Email.Provider = getEnv("EMAIL_PROVIDER", defaultEmailProvider)
+	config.Email.From = getEnv("EMAIL_FROM", config.SMTP.Email)
+	if config.Email.From == "" {
+		config.Email.From = "no-reply@localhost"
+	}
+	config.Email.FilePath = getEnv("EMAIL_FILE_PATH", "mail.mbox")
+	config.Email.TemplateDir = getEnv("EMAIL_TEMPLATE_DIR", "")
+	config.Email.DefaultLocale = getEnv("EMAIL_DEFAULT_LOCALE", "en")
+	config.Email.Workers = getEnvAsInt("EMAIL_WORKERS", 2)
+	config.Email.PollInterval = time.Duration(getEnvAsInt("EMAIL_POLL_INTERVAL_SECONDS", 5)) * time.Second
+	config.Email.BatchSize = getEnvAsInt("EMAIL_BATCH_SIZE", 10)
+	config.Email.MaxAttempts = getEnvAsInt("EMAIL_MAX_ATTEMPTS", 8)
+
+	// Phone notifications. Outside production codes are only logged unless
+	// a provider is chosen explicitly; production has none by default.
+	defaultNotifyProvider := "console"
+	if os.Getenv("APP_ENV") == "production" {
+		defaultNotifyProvider = ""
+	}
+	config.Notify.Provider = getEnv("NOTIFY_PROVIDER", defaultNotifyProvider)
+	config.Notify.From = getEnv("NOTIFY_FROM", "")
+	config.Notify.WebhookURL = getEnv("NOTIFY_WEBHOOK_URL", "")
+	config.Notify.WebhookSecret = getEnv("NOTIFY_WEBHOOK_SECRET", "")
+	config.Notify.TwilioSID = getEnv("TWILIO_ACCOUNT_SID", "")
+	config.Notify.TwilioToken = getEnv("TWILIO_AUTH_TOKEN", "")
+	config.Notify.OTPChannel = strings.ToLower(getEnv("LOGIN_OTP_CHANNEL", "email"))
+	config.Notify.VerificationCooldown = time.Duration(getEnvAsInt("PHONE_VERIFICATION_COOLDOWN_SECONDS", 60)) * time.Second
+	config.Notify.VerificationDailyLimit = getEnvAsInt("PHONE_VERIFICATION_DAILY_LIMIT", 5)
+
+	// Login risk scoring
+	config.Risk.Enabled = getEnvAsBool("RISK_SCORING_ENABLED", false)
+	config.Risk.GeoIPDatabase = getEnv("GEOIP_DATABASE_PATH", "")
+	config.Risk.IPReputationList = getEnv("IP_REPUTATION_LIST_PATH", "")
+	config.Risk.StepUpThreshold = getEnvAsInt("RISK_STEP_UP_THRESHOLD", 50)
+	config.Risk.MaxTravelSpeed = float64(getEnvAsInt("RISK_MAX_TRAVEL_SPEED_KMH", 900))
+
+	// API key settings
+	config.APIKeys.DefaultExpiration = time.Duration(getEnvAsInt("API_KEY_DEFAULT_EXPIRATION_DAYS", 90)) * 24 * time.Hour
+
+	// Authorization policy settings
+	config.Authz.PolicyDir = getEnv("POLICY_DIR", "policies")
+	config.Authz.DecisionCacheTTL = time.Duration(getEnvAsInt("AUTHZ_DECISION_CACHE_SECONDS", 60)) * time.Second
+
 	// Application settings
 	config.App.Environment = getEnv("ENV", "development")
 	config.App.Port = getEnv("PORT", "8080")
 	config.App.GinMode = getEnv("GIN_MODE", "release")
+	config.App.BaseURL = strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8080"), "/")
+	appEnv := os.Getenv("APP_ENV")
+	config.App.DevTools = appEnv == "development" || appEnv == "test"
 
 	return config, validateConfig(config)
 }
@@ -131,6 +295,17 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsList splits a comma-separated value, dropping empty entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func validateConfig(config *Config) error {
 	if config.JWT.Secret == "" {
 		return errors.New("JWT_SECRET is required")
@@ -138,6 +313,46 @@ func validateConfig(config *Config) error {
 	if config.Database.Password == "" {
 		return errors.New("DB_PASSWORD is required")
 	}
+	if config.Sessions.LimitStrategy != SessionLimitReject && config.Sessions.LimitStrategy != SessionLimitEvictOldest {
+		return errors.New("SESSION_LIMIT_STRATEGY must be reject or evict_oldest")
+	}
+	switch config.Registration.VerificationMode {
+	case EmailVerificationCode, EmailVerificationLink, EmailVerificationBoth:
+	default:
+		return errors.New("EMAIL_VERIFICATION_MODE must be code, link or both")
+	}
+	if config.Registration.VerificationLinkTTL <= 0 {
+		return errors.New("EMAIL_VERIFICATION_LINK_HOURS must be positive")
+	}
+	if config.Account.PurgeInterval <= 0 {
+		return errors.New("ACCOUNT_PURGE_INTERVAL_MINUTES must be positive")
+	}
+	if config.Registration.UsernameCheckLimit < 1 || config.Registration.UsernameCheckWindow <= 0 {
+		return errors.New("USERNAME_CHECK_LIMIT and USERNAME_CHECK_WINDOW_SECONDS must be positive")
+	}
+	switch config.Cookie.SameSite {
+	case "lax", "strict":
+	case "none":
+		if !config.Cookie.Secure {
+			return errors.New("COOKIE_SAMESITE=none requires COOKIE_SECURE=true")
+		}
+	default:
+		return errors.New("COOKIE_SAMESITE must be lax, strict or none")
+	}
+	if config.Email.Workers < 1 || config.Email.BatchSize < 1 || config.Email.MaxAttempts < 1 || config.Email.PollInterval <= 0 {
+		return errors.New("EMAIL_WORKERS, EMAIL_POLL_INTERVAL_SECONDS, EMAIL_BATCH_SIZE and EMAIL_MAX_ATTEMPTS must be positive")
+	}
+	switch config.Notify.OTPChannel {
+	case "email", "sms", "whatsapp", "voice":
+	default:
+		return errors.New("LOGIN_OTP_CHANNEL must be email, sms, whatsapp or voice")
+	}
+	if config.Notify.VerificationCooldown < 0 || config.Notify.VerificationDailyLimit < 0 {
+		return errors.New("PHONE_VERIFICATION_COOLDOWN_SECONDS and PHONE_VERIFICATION_DAILY_LIMIT must not be negative")
+	}
+	if config.Risk.Enabled && config.Risk.StepUpThreshold < 1 {
+		return errors.New("RISK_STEP_UP_THRESHOLD must be positive")
+	}
 	// Add more validation as needed
 	return nil
 }
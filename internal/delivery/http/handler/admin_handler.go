@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminUsecase domain.AdminUsecase
+}
+
+type suspendUserRequest struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+type listUsersQuery struct {
+	Email          string `form:"email"`
+	Name           string `form:"name"`
+	Status         string `form:"status"`
+	CreatedFrom    string `form:"created_from"`
+	CreatedTo      string `form:"created_to"`
+	IncludeDeleted bool   `form:"include_deleted"`
+	Page           int    `form:"page"`
+	PageSize       int    `form:"page_size"`
+}
+
+func NewAdminHandler(adminUsecase domain.AdminUsecase) *AdminHandler {
+	return &AdminHandler{
+		adminUsecase: adminUsecase,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var query listUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	createdFrom, err := parseTimeParam("created_from", query.CreatedFrom)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+	createdTo, err := parseTimeParam("created_to", query.CreatedTo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	page, err := h.adminUsecase.ListUsers(domain.UserFilter{
+		Email:          query.Email,
+		Name:           query.Name,
+		Status:         domain.UserStatus(query.Status),
+		CreatedAfter:   createdFrom,
+		CreatedBefore:  createdTo,
+		IncludeDeleted: query.IncludeDeleted,
+		Page:           query.Page,
+		PageSize:       query.PageSize,
+	})
+	if err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to list users", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Users retrieved successfully", page))
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	includeDeleted := c.DefaultQuery("include_deleted", "false") == "true"
+
+	user, err := h.adminUsecase.GetUser(c.Param("id"), includeDeleted)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to get user", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User retrieved successfully", user))
+}
+
+func (h *AdminHandler) ActivateUser(c *gin.Context) {
+	if err := h.adminUsecase.ActivateUser(c.Param("id")); err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to activate user", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User activated successfully", nil))
+}
+
+func (h *AdminHandler) DeactivateUser(c *gin.Context) {
+	if h.isSelf(c) {
+		c.JSON(http.StatusBadRequest, response.Error("Administrators cannot deactivate their own account", nil))
+		return
+	}
+
+	if err := h.adminUsecase.DeactivateUser(c.Param("id")); err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to deactivate user", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User deactivated successfully", nil))
+}
+
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	if h.isSelf(c) {
+		c.JSON(http.StatusBadRequest, response.Error("Administrators cannot suspend their own account", nil))
+		return
+	}
+
+	var req suspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.adminUsecase.SuspendUser(c.Param("id"), req.Reason, req.Until); err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to suspend user", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User suspended successfully", nil))
+}
+
+func (h *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	if err := h.adminUsecase.ForcePasswordReset(c.Param("id")); err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to force password reset", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Password reset required; a reset code has been sent to the user", nil))
+}
+
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	if h.isSelf(c) {
+		c.JSON(http.StatusBadRequest, response.Error("Administrators cannot delete their own account here", nil))
+		return
+	}
+
+	permanent := c.DefaultQuery("permanent", "false") == "true"
+
+	if err := h.adminUsecase.DeleteUser(c.GetString("user_id"), c.Param("id"), permanent); err != nil {
+		c.JSON(adminErrorStatus(err), response.Error("Failed to delete user", err))
+		return
+	}
+
+	message := "User soft deleted successfully"
+	if permanent {
+		message = "User permanently deleted"
+	}
+
+	c.JSON(http.StatusOK, response.Success(message, nil))
+}
+
+func (h *AdminHandler) isSelf(c *gin.Context) bool {
+	return c.Param("id") == c.GetString("user_id")
+}
+
+// parseTimeParam accepts either a date (2006-01-02) or an RFC3339 timestamp
+func parseTimeParam(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC3339 timestamp", name)
+}
+
+func adminErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidUserStatus), errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
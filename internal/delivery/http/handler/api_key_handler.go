@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase domain.APIKeyUsecase
+}
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Kind      string     `json:"kind"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// createAPIKeyResponse carries the secret, which is shown only once
+type createAPIKeyResponse struct {
+	*domain.APIKey
+	Key string `json:"key"`
+}
+
+func NewAPIKeyHandler(apiKeyUsecase domain.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyUsecase.ListAPIKeys(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(apiKeyErrorStatus(err), response.Error("Failed to list API keys", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("API keys retrieved successfully", keys))
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	key := &domain.APIKey{
+		Name:      req.Name,
+		Kind:      domain.APIKeyKind(req.Kind),
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	secret, err := h.apiKeyUsecase.CreateAPIKey(c.GetString("user_id"), key)
+	if err != nil {
+		c.JSON(apiKeyErrorStatus(err), response.Error("Failed to create API key", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success(
+		"API key created successfully. Store the key now, it will not be shown again",
+		createAPIKeyResponse{APIKey: key, Key: secret},
+	))
+}
+
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	if err := h.apiKeyUsecase.DeleteAPIKey(c.GetString("user_id"), c.Param("id")); err != nil {
+		c.JSON(apiKeyErrorStatus(err), response.Error("Failed to delete API key", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("API key deleted successfully", nil))
+}
+
+func apiKeyErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
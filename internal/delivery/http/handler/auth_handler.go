@@ -1,27 +1,47 @@
 package handler
 
 import (
+	"auth-service/internal/config"
 	"auth-service/internal/delivery/http/response"
 	"auth-service/internal/domain"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
 	authUsecase domain.UserUsecase
+	config      *config.Config
 }
 
 type registerRequest struct {
 	Email    string `json:"email" binding:"required,email"`
+	Username string `json:"username"`
 	Name     string `json:"name" binding:"required"`
 	Password string `json:"password" binding:"required,min=8"`
 }
 
+// loginRequest takes an email address or a username as the identifier.
+// Email is still accepted on its own for existing clients.
 type loginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Identifier string `json:"identifier" binding:"required_without=Email"`
+	Email      string `json:"email" binding:"omitempty,email"`
+	Password   string `json:"password" binding:"required"`
+	// UseCookie asks for the token in an HttpOnly cookie instead of the body
+	UseCookie bool `json:"useCookie"`
+}
+
+type loginOTPRequest struct {
+	Challenge string `json:"challenge" binding:"required"`
+	OTP       string `json:"otp" binding:"required,len=6"`
+	UseCookie bool   `json:"useCookie"`
+}
+
+type usernameQuery struct {
+	Username string `form:"username" binding:"required"`
 }
 
 type otpRequest struct {
@@ -29,9 +49,16 @@ type otpRequest struct {
 	OTP   string `json:"otp" binding:"required,len=6"`
 }
 
-func NewAuthHandler(authUsecase domain.UserUsecase) *AuthHandler {
+type resetPasswordRequest struct {
+	Email       string `json:"email" binding:"required,email"`
+	Code        string `json:"code" binding:"required,len=6"`
+	NewPassword string `json:"newPassword" binding:"required,min=8"`
+}
+
+func NewAuthHandler(authUsecase domain.UserUsecase, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
 		authUsecase: authUsecase,
+		config:      cfg,
 	}
 }
 
@@ -50,11 +77,29 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	user := &domain.User{
 		Email:    req.Email,
+		Username: req.Username,
 		Name:     req.Name,
+		Locale:   requestLocale(c),
 		Password: req.Password,
 	}
 
 	if err := h.authUsecase.Register(user); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRegistrationClosed):
+			c.JSON(http.StatusForbidden, response.Error("Registration failed", err))
+			return
+		case errors.Is(err, domain.ErrEmailDomainBlocked), errors.Is(err, domain.ErrDisposableEmail):
+			c.JSON(http.StatusBadRequest, response.Error("Registration failed", err))
+			return
+		case errors.Is(err, domain.ErrUserAlreadyExists), errors.Is(err, domain.ErrUsernameTaken):
+			c.JSON(http.StatusConflict, response.Error("Registration failed", err))
+			return
+		}
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, response.Error("Registration failed", err))
+			return
+		}
 		log.Printf("Registration error: %v", err)
 		c.JSON(http.StatusInternalServerError, response.Error("Registration failed", err))
 		return
@@ -62,7 +107,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	message := "Registration successful. "
 	if c.GetString("APP_ENV") == "development" {
-		message += "Check the server logs for the OTP code."
+		message += "Check the server logs for the verification email."
+	} else if h.config.Registration.VerificationMode == config.EmailVerificationLink {
+		message += "Please check your email for the verification link."
 	} else {
 		message += "Please check your email for the OTP code."
 	}
@@ -77,9 +124,100 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authUsecase.Login(req.Email, req.Password)
+	if req.UseCookie && !h.config.Cookie.Enabled {
+		c.JSON(http.StatusBadRequest, response.Error("Cookie sessions are not enabled", nil))
+		return
+	}
+
+	identifier := req.Identifier
+	if identifier == "" {
+		identifier = req.Email
+	}
+
+	token, err := h.authUsecase.Login(identifier, req.Password, clientInfo(c))
+	var stepUp *domain.StepUpRequiredError
+	if errors.As(err, &stepUp) {
+		message := "Additional verification required. Please check your email for the OTP code."
+		if stepUp.Channel != "email" {
+			message = "Additional verification required. Please check your phone for the OTP code."
+		}
+		c.JSON(http.StatusAccepted, response.Success(message, gin.H{
+			"stepUp":    "otp",
+			"channel":   stepUp.Channel,
+			"challenge": stepUp.Challenge,
+		}))
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInactiveUser),
+			errors.Is(err, domain.ErrSuspendedUser),
+			errors.Is(err, domain.ErrPasswordResetRequired),
+			errors.Is(err, domain.ErrRestoreExpired),
+			errors.Is(err, domain.ErrSessionLimitReached):
+			c.JSON(http.StatusForbidden, response.Error("Login failed", err))
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, response.Error("Login failed", err))
+		default:
+			log.Printf("Login error: %v", err)
+			c.JSON(http.StatusInternalServerError, response.Error("Login failed", err))
+		}
+		return
+	}
+
+	h.respondWithToken(c, token, req.UseCookie)
+}
+
+// VerifyLoginOTP completes a login that required step-up verification
+func (h *AuthHandler) VerifyLoginOTP(c *gin.Context) {
+	var req loginOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if req.UseCookie && !h.config.Cookie.Enabled {
+		c.JSON(http.StatusBadRequest, response.Error("Cookie sessions are not enabled", nil))
+		return
+	}
+
+	token, err := h.authUsecase.VerifyLoginOTP(req.Challenge, req.OTP, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, response.Error("Login failed", err))
+		switch {
+		case errors.Is(err, domain.ErrInvalidToken),
+			errors.Is(err, domain.ErrInvalidOTP),
+			errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusUnauthorized, response.Error("Login failed", err))
+		case errors.Is(err, domain.ErrTooManyOTPAttempts):
+			c.JSON(http.StatusTooManyRequests, response.Error("Login failed", err))
+		case errors.Is(err, domain.ErrInactiveUser),
+			errors.Is(err, domain.ErrSuspendedUser),
+			errors.Is(err, domain.ErrPasswordResetRequired),
+			errors.Is(err, domain.ErrSessionLimitReached):
+			c.JSON(http.StatusForbidden, response.Error("Login failed", err))
+		default:
+			log.Printf("Login error: %v", err)
+			c.JSON(http.StatusInternalServerError, response.Error("Login failed", err))
+		}
+		return
+	}
+
+	h.respondWithToken(c, token, req.UseCookie)
+}
+
+// respondWithToken hands a new access token to the client, either in the
+// body or, for cookie sessions, as an HttpOnly cookie
+func (h *AuthHandler) respondWithToken(c *gin.Context, token string, useCookie bool) {
+	if useCookie {
+		csrfToken, err := setSessionCookies(c, h.config, token)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error("Login failed", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success("Login successful", gin.H{
+			"csrfToken": csrfToken,
+		}))
 		return
 	}
 
@@ -96,13 +234,88 @@ func (h *AuthHandler) VerifyOTP(c *gin.Context) {
 	}
 
 	if err := h.authUsecase.VerifyOTP(req.Email, req.OTP); err != nil {
-		c.JSON(http.StatusBadRequest, response.Error("OTP verification failed", err))
+		switch {
+		case errors.Is(err, domain.ErrInvalidOTP):
+			c.JSON(http.StatusBadRequest, response.Error("OTP verification failed", err))
+		case errors.Is(err, domain.ErrTooManyOTPAttempts):
+			c.JSON(http.StatusTooManyRequests, response.Error("OTP verification failed", err))
+		default:
+			log.Printf("OTP verification error: %v", err)
+			c.JSON(http.StatusInternalServerError, response.Error("OTP verification failed", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Email verified successfully", nil))
+}
+
+// CheckUsername reports whether a username can be taken, for sign-up forms
+func (h *AuthHandler) CheckUsername(c *gin.Context) {
+	var query usernameQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	available, err := h.authUsecase.CheckUsernameAvailable(query.Username)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, response.Error("Invalid username", err))
+		default:
+			c.JSON(http.StatusInternalServerError, response.Error("Failed to check username", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Username availability checked", gin.H{
+		"username":  strings.TrimSpace(query.Username),
+		"available": available,
+	}))
+}
+
+// VerifyEmail handles the verification link emailed at registration
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", errors.New("token is required")))
+		return
+	}
+
+	if err := h.authUsecase.VerifyEmail(token); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidToken):
+			c.JSON(http.StatusBadRequest, response.Error("Email verification failed", err))
+		case errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, response.Error("Email verification failed", err))
+		case errors.Is(err, domain.ErrOTPAlreadyVerified):
+			c.JSON(http.StatusConflict, response.Error("Email verification failed", err))
+		default:
+			c.JSON(http.StatusInternalServerError, response.Error("Email verification failed", err))
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, response.Success("Email verified successfully", nil))
 }
 
+// ConfirmEmailChange handles the confirmation link sent to a new address
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", errors.New("token is required")))
+		return
+	}
+
+	if err := h.authUsecase.ConfirmEmailChangeLink(token); err != nil {
+		c.JSON(emailChangeErrorStatus(err), response.Error("Failed to confirm email change", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Email changed successfully", nil))
+}
+
 func (h *AuthHandler) ResendOTP(c *gin.Context) {
 	var req struct {
 		Email string `json:"email" binding:"required,email"`
@@ -119,3 +332,46 @@ func (h *AuthHandler) ResendOTP(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.Success("OTP resent successfully", nil))
 }
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.authUsecase.ResetPassword(req.Email, req.Code, req.NewPassword); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrTooManyOTPAttempts) {
+			status = http.StatusTooManyRequests
+		}
+		c.JSON(status, response.Error("Password reset failed", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Password reset successfully", nil))
+}
+
+func (h *AuthHandler) RestoreAccount(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", errors.New("token is required")))
+		return
+	}
+
+	if err := h.authUsecase.RestoreAccount(token); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidToken), errors.Is(err, domain.ErrRestoreExpired):
+			c.JSON(http.StatusBadRequest, response.Error("Account restore failed", err))
+		case errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, response.Error("Account restore failed", err))
+		case errors.Is(err, domain.ErrUserAlreadyExists):
+			c.JSON(http.StatusConflict, response.Error("Account restore failed", err))
+		default:
+			c.JSON(http.StatusInternalServerError, response.Error("Account restore failed", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Account restored successfully", nil))
+}
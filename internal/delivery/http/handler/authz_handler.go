@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkOnBehalfPermission lets trusted services ask about subjects other
+// than the caller
+const checkOnBehalfPermission = "authz:check"
+
+type AuthzHandler struct {
+	authzUsecase domain.AuthzUsecase
+}
+
+type authzCheckRequest struct {
+	Subject  *domain.Subject  `json:"subject"`
+	Action   string           `json:"action" binding:"required"`
+	Resource *domain.Resource `json:"resource" binding:"required"`
+}
+
+func NewAuthzHandler(authzUsecase domain.AuthzUsecase) *AuthzHandler {
+	return &AuthzHandler{
+		authzUsecase: authzUsecase,
+	}
+}
+
+// Check evaluates the policies for the caller, or for an explicit subject
+// when the caller holds authz:check
+func (h *AuthzHandler) Check(c *gin.Context) {
+	var req authzCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	subject := req.Subject
+	if subject == nil {
+		subject = callerSubject(c)
+	} else if !hasPermission(c, checkOnBehalfPermission) {
+		c.JSON(http.StatusForbidden, response.Error("Missing required permission: "+checkOnBehalfPermission, nil))
+		return
+	}
+
+	decision, err := h.authzUsecase.Authorize(subject, req.Action, req.Resource)
+	if err != nil {
+		c.JSON(authzErrorStatus(err), response.Error("Failed to evaluate policies", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Authorization decision evaluated", decision))
+}
+
+// callerSubject describes the authenticated user from the token claims
+func callerSubject(c *gin.Context) *domain.Subject {
+	return &domain.Subject{
+		ID:          c.GetString("user_id"),
+		Roles:       c.GetStringSlice("roles"),
+		Permissions: c.GetStringSlice("permissions"),
+		Groups:      c.GetStringSlice("groups"),
+		OrgID:       c.GetString("org_id"),
+		OrgRole:     c.GetString("org_role"),
+	}
+}
+
+func hasPermission(c *gin.Context, permission string) bool {
+	for _, granted := range c.GetStringSlice("permissions") {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func authzErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setSessionCookies stores the access token in an HttpOnly cookie and
+// issues a fresh CSRF token for the double-submit check. The CSRF cookie
+// stays readable by scripts so the client can echo it in X-CSRF-Token.
+func setSessionCookies(c *gin.Context, cfg *config.Config, token string) (string, error) {
+	csrfToken, err := utils.GenerateCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	maxAge := int(utils.AccessTokenLifetime.Seconds())
+	http.SetCookie(c.Writer, sessionCookie(cfg, utils.AccessTokenCookie, token, maxAge, true))
+	http.SetCookie(c.Writer, sessionCookie(cfg, utils.CSRFCookie, csrfToken, maxAge, false))
+
+	return csrfToken, nil
+}
+
+// clearSessionCookies expires the access token and CSRF cookies
+func clearSessionCookies(c *gin.Context, cfg *config.Config) {
+	http.SetCookie(c.Writer, sessionCookie(cfg, utils.AccessTokenCookie, "", -1, true))
+	http.SetCookie(c.Writer, sessionCookie(cfg, utils.CSRFCookie, "", -1, false))
+}
+
+func sessionCookie(cfg *config.Config, name, value string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cfg.Cookie.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Cookie.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sameSiteMode(cfg.Cookie.SameSite),
+	}
+}
+
+func sameSiteMode(value string) http.SameSite {
+	switch value {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/email"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DevMailboxHandler exposes mail caught in development and test so
+// end-to-end tests can read codes and links without scraping logs
+type DevMailboxHandler struct {
+	mailbox *email.Mailbox
+}
+
+func NewDevMailboxHandler(mailbox *email.Mailbox) *DevMailboxHandler {
+	return &DevMailboxHandler{
+		mailbox: mailbox,
+	}
+}
+
+// ListMessages returns caught mail newest first, limited to one recipient
+// by the to query parameter
+func (h *DevMailboxHandler) ListMessages(c *gin.Context) {
+	c.JSON(http.StatusOK, response.Success("Mailbox retrieved successfully", gin.H{
+		"messages": h.mailbox.Messages(c.Query("to")),
+	}))
+}
+
+// ClearMessages empties the mailbox, or only one recipient's mail when to
+// is given
+func (h *DevMailboxHandler) ClearMessages(c *gin.Context) {
+	c.JSON(http.StatusOK, response.Success("Mailbox cleared successfully", gin.H{
+		"deleted": h.mailbox.Clear(c.Query("to")),
+	}))
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/email"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailPreviewHandler renders emails with sample data so templates can be
+// checked in a browser during development
+type EmailPreviewHandler struct {
+	renderer *email.Renderer
+}
+
+func NewEmailPreviewHandler(renderer *email.Renderer) *EmailPreviewHandler {
+	return &EmailPreviewHandler{
+		renderer: renderer,
+	}
+}
+
+func (h *EmailPreviewHandler) ListTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, response.Success("Email templates retrieved successfully", gin.H{
+		"templates": email.Templates,
+		"locales":   h.renderer.Locales(),
+	}))
+}
+
+// PreviewTemplate renders one email. The locale query parameter overrides
+// Accept-Language, and format selects the html or text part instead of
+// the whole message as JSON.
+func (h *EmailPreviewHandler) PreviewTemplate(c *gin.Context) {
+	name := c.Param("name")
+	data := email.PreviewData(name)
+	if data == nil {
+		c.JSON(http.StatusNotFound, response.Error("Failed to preview email", errors.New("email template not found")))
+		return
+	}
+
+	locale := c.Query("locale")
+	if locale == "" {
+		locale = requestLocale(c)
+	}
+
+	message, err := h.renderer.Render(name, locale, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to preview email", err))
+		return
+	}
+
+	switch c.Query("format") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(message.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(message.Text))
+	default:
+		c.JSON(http.StatusOK, response.Success("Email rendered successfully", message))
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailQueueHandler struct {
+	queueUsecase domain.EmailQueueUsecase
+}
+
+func NewEmailQueueHandler(queueUsecase domain.EmailQueueUsecase) *EmailQueueHandler {
+	return &EmailQueueHandler{
+		queueUsecase: queueUsecase,
+	}
+}
+
+type emailQueueQuery struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// ListDeadLetters lists messages that ran out of delivery attempts
+func (h *EmailQueueHandler) ListDeadLetters(c *gin.Context) {
+	var query emailQueueQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	page, err := h.queueUsecase.ListDeadLetters(query.Page, query.PageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to list dead letters", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Dead letters retrieved successfully", page))
+}
+
+func (h *EmailQueueHandler) GetEmail(c *gin.Context) {
+	outbound, err := h.queueUsecase.GetEmail(c.Param("id"))
+	if err != nil {
+		c.JSON(emailQueueErrorStatus(err), response.Error("Failed to get email", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Email retrieved successfully", outbound))
+}
+
+func (h *EmailQueueHandler) ReplayEmail(c *gin.Context) {
+	if err := h.queueUsecase.ReplayEmail(c.Param("id")); err != nil {
+		c.JSON(emailQueueErrorStatus(err), response.Error("Failed to replay email", err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.Success("Email queued for delivery", nil))
+}
+
+func emailQueueErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrEmailNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrEmailNotDead):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
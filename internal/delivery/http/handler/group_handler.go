@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GroupHandler struct {
+	groupUsecase domain.GroupUsecase
+}
+
+type createGroupRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type addGroupMemberRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
+
+type addGroupChildRequest struct {
+	GroupID string `json:"groupId" binding:"required"`
+}
+
+func NewGroupHandler(groupUsecase domain.GroupUsecase) *GroupHandler {
+	return &GroupHandler{
+		groupUsecase: groupUsecase,
+	}
+}
+
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	groups, err := h.groupUsecase.ListGroups()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to list groups", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Groups retrieved successfully", groups))
+}
+
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	var req createGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	group := &domain.Group{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.groupUsecase.CreateGroup(group); err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to create group", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success("Group created successfully", group))
+}
+
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	group, err := h.groupUsecase.GetGroup(c.Param("id"))
+	if err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to get group", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Group retrieved successfully", group))
+}
+
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	if err := h.groupUsecase.DeleteGroup(c.Param("id")); err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to delete group", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Group deleted successfully", nil))
+}
+
+func (h *GroupHandler) AddMember(c *gin.Context) {
+	var req addGroupMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.groupUsecase.AddMember(c.Param("id"), req.UserID); err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to add group member", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Group member added successfully", nil))
+}
+
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	if err := h.groupUsecase.RemoveMember(c.Param("id"), c.Param("userId")); err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to remove group member", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Group member removed successfully", nil))
+}
+
+func (h *GroupHandler) AddChild(c *gin.Context) {
+	var req addGroupChildRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.groupUsecase.AddChild(c.Param("id"), req.GroupID); err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to nest group", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Group nested successfully", nil))
+}
+
+func (h *GroupHandler) RemoveChild(c *gin.Context) {
+	if err := h.groupUsecase.RemoveChild(c.Param("id"), c.Param("childId")); err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to remove nested group", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Nested group removed successfully", nil))
+}
+
+func (h *GroupHandler) GetUserGroups(c *gin.Context) {
+	groups, err := h.groupUsecase.GetUserGroups(c.Param("id"))
+	if err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to get user groups", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User groups retrieved successfully", groups))
+}
+
+func (h *GroupHandler) GetMyGroups(c *gin.Context) {
+	groups, err := h.groupUsecase.GetUserGroups(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(groupErrorStatus(err), response.Error("Failed to get groups", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Groups retrieved successfully", groups))
+}
+
+func groupErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrGroupNotFound),
+		errors.Is(err, domain.ErrNotInGroup):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrGroupAlreadyExists),
+		errors.Is(err, domain.ErrAlreadyInGroup),
+		errors.Is(err, domain.ErrGroupCycle):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
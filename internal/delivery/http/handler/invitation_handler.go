@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationUsecase domain.InvitationUsecase
+}
+
+type createInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role"`
+}
+
+type acceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func NewInvitationHandler(invitationUsecase domain.InvitationUsecase) *InvitationHandler {
+	return &InvitationHandler{
+		invitationUsecase: invitationUsecase,
+	}
+}
+
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req createInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	role := domain.OrgRole(req.Role)
+	if role == "" {
+		role = domain.OrgRoleMember
+	}
+
+	invitation, err := h.invitationUsecase.CreateInvitation(c.GetString("org_id"), c.GetString("user_id"), req.Email, role)
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), response.Error("Failed to create invitation", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success("Invitation sent successfully", invitation))
+}
+
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.invitationUsecase.ListInvitations(c.GetString("org_id"))
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), response.Error("Failed to list invitations", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Invitations retrieved successfully", invitations))
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	if err := h.invitationUsecase.RevokeInvitation(c.GetString("org_id"), c.Param("id")); err != nil {
+		c.JSON(invitationErrorStatus(err), response.Error("Failed to revoke invitation", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Invitation revoked successfully", nil))
+}
+
+func (h *InvitationHandler) GetInvitation(c *gin.Context) {
+	invitation, err := h.invitationUsecase.GetInvitation(c.Query("token"))
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), response.Error("Invalid invitation", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Invitation retrieved successfully", invitation))
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req acceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	token, err := h.invitationUsecase.AcceptInvitation(req.Token, req.Name, req.Password, clientInfo(c))
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), response.Error("Failed to accept invitation", err))
+		return
+	}
+
+	// Existing accounts join without a session and sign in as usual
+	if token == "" {
+		c.JSON(http.StatusOK, response.Success("Invitation accepted, please sign in to continue", gin.H{
+			"loginRequired": true,
+		}))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Invitation accepted successfully", gin.H{
+		"token": token,
+	}))
+}
+
+func invitationErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr),
+		errors.Is(err, domain.ErrInvalidOrgRole),
+		errors.Is(err, domain.ErrInvalidToken),
+		errors.Is(err, domain.ErrInvitationInvalid),
+		errors.Is(err, domain.ErrEmailDomainBlocked),
+		errors.Is(err, domain.ErrDisposableEmail):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrNotMember),
+		errors.Is(err, domain.ErrInactiveUser),
+		errors.Is(err, domain.ErrSuspendedUser),
+		errors.Is(err, domain.ErrSessionLimitReached):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvitationNotFound), errors.Is(err, domain.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrAlreadyMember), errors.Is(err, domain.ErrUserAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
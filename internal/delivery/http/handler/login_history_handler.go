@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginHistoryHandler struct {
+	historyUsecase domain.LoginHistoryUsecase
+}
+
+func NewLoginHistoryHandler(historyUsecase domain.LoginHistoryUsecase) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		historyUsecase: historyUsecase,
+	}
+}
+
+type loginHistoryQuery struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+func (h *LoginHistoryHandler) ListLoginHistory(c *gin.Context) {
+	var query loginHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	page, err := h.historyUsecase.ListLoginHistory(c.GetString("user_id"), query.Page, query.PageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to list login history", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Login history retrieved successfully", page))
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"auth-service/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	orgUsecase domain.OrganizationUsecase
+	config     *config.Config
+}
+
+type createOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+	Slug string `json:"slug"`
+}
+
+type addMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type updateMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func NewOrganizationHandler(orgUsecase domain.OrganizationUsecase, cfg *config.Config) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgUsecase: orgUsecase,
+		config:     cfg,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req createOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	org := &domain.Organization{
+		Name: req.Name,
+		Slug: req.Slug,
+	}
+
+	if err := h.orgUsecase.CreateOrganization(c.GetString("user_id"), org); err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to create organization", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success("Organization created successfully", org))
+}
+
+func (h *OrganizationHandler) ListMemberships(c *gin.Context) {
+	memberships, err := h.orgUsecase.ListMemberships(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to list organizations", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Organizations retrieved successfully", memberships))
+}
+
+func (h *OrganizationHandler) SwitchOrganization(c *gin.Context) {
+	token, err := h.orgUsecase.SwitchOrganization(c.GetString("user_id"), c.Param("id"), c.GetString("session_family"))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to switch organization", err))
+		return
+	}
+
+	// Browser sessions get the tenant-scoped token back in their cookie
+	if c.GetString("auth_source") == utils.AuthSourceCookie {
+		csrfToken, err := setSessionCookies(c, h.config, token)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error("Failed to switch organization", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success("Switched organization successfully", gin.H{
+			"csrfToken": csrfToken,
+		}))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Switched organization successfully", gin.H{
+		"token": token,
+	}))
+}
+
+func (h *OrganizationHandler) GetCurrentOrganization(c *gin.Context) {
+	org, err := h.orgUsecase.GetOrganization(c.GetString("org_id"))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to get organization", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Organization retrieved successfully", gin.H{
+		"organization": org,
+		"role":         c.GetString("org_role"),
+	}))
+}
+
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	members, err := h.orgUsecase.ListMembers(c.GetString("org_id"))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to list members", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Members retrieved successfully", members))
+}
+
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	err := h.orgUsecase.AddMember(c.GetString("org_id"), c.GetString("user_id"), req.Email, domain.OrgRole(req.Role))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to add member", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success("Member added successfully", nil))
+}
+
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	var req updateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	err := h.orgUsecase.UpdateMemberRole(c.GetString("org_id"), c.GetString("user_id"), c.Param("userId"), domain.OrgRole(req.Role))
+	if err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to update member", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Member updated successfully", nil))
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	if err := h.orgUsecase.RemoveMember(c.GetString("org_id"), c.GetString("user_id"), c.Param("userId")); err != nil {
+		c.JSON(organizationErrorStatus(err), response.Error("Failed to remove member", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Member removed successfully", nil))
+}
+
+func organizationErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr), errors.Is(err, domain.ErrInvalidOrgRole):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrSessionRevoked):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden), errors.Is(err, domain.ErrNotMember):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrOrganizationNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrSlugTaken), errors.Is(err, domain.ErrAlreadyMember), errors.Is(err, domain.ErrLastOwner):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PhoneHandler struct {
+	phoneUsecase domain.PhoneUsecase
+}
+
+func NewPhoneHandler(phoneUsecase domain.PhoneUsecase) *PhoneHandler {
+	return &PhoneHandler{
+		phoneUsecase: phoneUsecase,
+	}
+}
+
+type phoneVerificationRequest struct {
+	PhoneNumber string `json:"phoneNumber" binding:"required"`
+	// Channel is sms, whatsapp or voice, defaulting to sms
+	Channel string `json:"channel"`
+}
+
+type phoneOTPRequest struct {
+	OTP string `json:"otp" binding:"required,len=6"`
+}
+
+// StartPhoneVerification sends a code to the number the user wants to add
+func (h *PhoneHandler) StartPhoneVerification(c *gin.Context) {
+	var req phoneVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.phoneUsecase.StartPhoneVerification(c.GetString("user_id"), req.PhoneNumber, req.Channel); err != nil {
+		c.JSON(phoneErrorStatus(err), response.Error("Failed to send verification code", err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.Success("Verification code sent", nil))
+}
+
+// ConfirmPhoneVerification saves the number once the code matches
+func (h *PhoneHandler) ConfirmPhoneVerification(c *gin.Context) {
+	var req phoneOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	user, err := h.phoneUsecase.ConfirmPhoneVerification(c.GetString("user_id"), req.OTP)
+	if err != nil {
+		c.JSON(phoneErrorStatus(err), response.Error("Failed to verify phone number", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Phone number verified successfully", user))
+}
+
+func (h *PhoneHandler) RemovePhoneNumber(c *gin.Context) {
+	if err := h.phoneUsecase.RemovePhoneNumber(c.GetString("user_id")); err != nil {
+		c.JSON(phoneErrorStatus(err), response.Error("Failed to remove phone number", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Phone number removed successfully", nil))
+}
+
+func phoneErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPhoneNumber),
+		errors.Is(err, domain.ErrInvalidChannel),
+		errors.Is(err, domain.ErrInvalidOTP):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrTooManyOTPAttempts),
+		errors.Is(err, domain.ErrVerificationCooldown),
+		errors.Is(err, domain.ErrVerificationLimitReached):
+		return http.StatusTooManyRequests
+	case errors.Is(err, domain.ErrPhoneNumberTaken):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrNotificationsUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	profileUsecase domain.ProfileUsecase
+}
+
+func NewProfileHandler(profileUsecase domain.ProfileUsecase) *ProfileHandler {
+	return &ProfileHandler{
+		profileUsecase: profileUsecase,
+	}
+}
+
+// updateProfileRequest changes only the fields present in the body
+type updateProfileRequest struct {
+	Name      *string `json:"name"`
+	Username  *string `json:"username"`
+	Locale    *string `json:"locale"`
+	Timezone  *string `json:"timezone"`
+	AvatarURL *string `json:"avatarUrl"`
+	// Attributes are merged into the stored ones; null removes a key
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type attributeSchemaRequest struct {
+	Schema json.RawMessage `json:"schema" binding:"required"`
+}
+
+// UpdateProfile changes the caller's profile and custom attributes
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var req updateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	user, err := h.profileUsecase.UpdateProfile(c.GetString("user_id"), domain.ProfileUpdate{
+		Name:       req.Name,
+		Username:   req.Username,
+		Locale:     req.Locale,
+		Timezone:   req.Timezone,
+		AvatarURL:  req.AvatarURL,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		c.JSON(profileErrorStatus(err), response.Error("Failed to update profile", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Profile updated successfully", user))
+}
+
+func (h *ProfileHandler) GetAttributeSchema(c *gin.Context) {
+	schema, err := h.profileUsecase.GetAttributeSchema()
+	if err != nil {
+		c.JSON(profileErrorStatus(err), response.Error("Failed to get attribute schema", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Attribute schema retrieved successfully", schema))
+}
+
+// SetAttributeSchema stores a new schema version for custom attributes
+func (h *ProfileHandler) SetAttributeSchema(c *gin.Context) {
+	var req attributeSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	schema, err := h.profileUsecase.SetAttributeSchema(c.GetString("user_id"), req.Schema)
+	if err != nil {
+		c.JSON(profileErrorStatus(err), response.Error("Failed to set attribute schema", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Attribute schema updated successfully", schema))
+}
+
+func profileErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr),
+		errors.Is(err, domain.ErrInvalidAttributeSchema):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrAttributeSchemaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrUsernameTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RBACHandler struct {
+	rbacUsecase domain.RBACUsecase
+}
+
+type createRoleRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type createPermissionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type grantPermissionRequest struct {
+	Permission string `json:"permission" binding:"required"`
+}
+
+type assignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func NewRBACHandler(rbacUsecase domain.RBACUsecase) *RBACHandler {
+	return &RBACHandler{
+		rbacUsecase: rbacUsecase,
+	}
+}
+
+func (h *RBACHandler) ListRoles(c *gin.Context) {
+	roles, err := h.rbacUsecase.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to list roles", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Roles retrieved successfully", roles))
+}
+
+func (h *RBACHandler) CreateRole(c *gin.Context) {
+	var req createRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	role := &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.rbacUsecase.CreateRole(role); err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to create role", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success("Role created successfully", role))
+}
+
+func (h *RBACHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.rbacUsecase.ListPermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to list permissions", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Permissions retrieved successfully", permissions))
+}
+
+func (h *RBACHandler) CreatePermission(c *gin.Context) {
+	var req createPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	permission := &domain.Permission{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.rbacUsecase.CreatePermission(permission); err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to create permission", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success("Permission created successfully", permission))
+}
+
+func (h *RBACHandler) GrantPermission(c *gin.Context) {
+	var req grantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.rbacUsecase.GrantPermission(c.Param("name"), req.Permission); err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to grant permission", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Permission granted successfully", nil))
+}
+
+func (h *RBACHandler) RevokePermission(c *gin.Context) {
+	if err := h.rbacUsecase.RevokePermission(c.Param("name"), c.Param("permission")); err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to revoke permission", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Permission revoked successfully", nil))
+}
+
+func (h *RBACHandler) GetUserRoles(c *gin.Context) {
+	userID := c.Param("id")
+
+	roles, err := h.rbacUsecase.GetUserRoles(userID)
+	if err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to get user roles", err))
+		return
+	}
+
+	permissions, err := h.rbacUsecase.GetUserPermissions(userID)
+	if err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to get user permissions", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User roles retrieved successfully", gin.H{
+		"roles":       roles,
+		"permissions": permissions,
+	}))
+}
+
+func (h *RBACHandler) AssignRole(c *gin.Context) {
+	var req assignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	if err := h.rbacUsecase.AssignRole(c.Param("id"), req.Role); err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to assign role", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Role assigned successfully", nil))
+}
+
+func (h *RBACHandler) RevokeRole(c *gin.Context) {
+	if err := h.rbacUsecase.RevokeRole(c.Param("id"), c.Param("role")); err != nil {
+		c.JSON(rbacErrorStatus(err), response.Error("Failed to revoke role", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Role revoked successfully", nil))
+}
+
+func rbacErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrRoleNotFound),
+		errors.Is(err, domain.ErrRoleNotAssigned),
+		errors.Is(err, domain.ErrPermissionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrRoleAlreadyExists),
+		errors.Is(err, domain.ErrPermissionAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
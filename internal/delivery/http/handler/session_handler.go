@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/delivery/http/response"
+	"auth-service/internal/domain"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionUsecase domain.SessionUsecase
+	config         *config.Config
+}
+
+func NewSessionHandler(sessionUsecase domain.SessionUsecase, cfg *config.Config) *SessionHandler {
+	return &SessionHandler{
+		sessionUsecase: sessionUsecase,
+		config:         cfg,
+	}
+}
+
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.sessionUsecase.ListSessions(c.GetString("user_id"), c.GetString("session_family"))
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), response.Error("Failed to list sessions", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Sessions retrieved successfully", sessions))
+}
+
+// RevokeSession signs the session out everywhere. Revoking the current
+// session logs the caller out.
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	if err := h.sessionUsecase.RevokeSession(c.GetString("user_id"), c.Param("id")); err != nil {
+		c.JSON(sessionErrorStatus(err), response.Error("Failed to revoke session", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Session revoked successfully", nil))
+}
+
+// Logout ends the current session, freeing its slot under the concurrent
+// session limit, and clears the session cookies
+func (h *SessionHandler) Logout(c *gin.Context) {
+	if err := h.sessionUsecase.Logout(c.GetString("user_id"), c.GetString("session_family")); err != nil {
+		c.JSON(sessionErrorStatus(err), response.Error("Failed to log out", err))
+		return
+	}
+
+	clearSessionCookies(c, h.config)
+	c.JSON(http.StatusOK, response.Success("Logged out successfully", nil))
+}
+
+// revokeSessionsPage asks for confirmation before the "this wasn't me" link
+// signs the user out, so mail scanners and link previews that follow the
+// link don't trigger it
+var revokeSessionsPage = template.Must(template.New("revoke-sessions").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<title>Sign out all sessions</title>
+</head>
+<body style="font-family:Helvetica,Arial,sans-serif;max-width:560px;margin:40px auto;padding:0 24px;">
+{{if .Action}}<h2>Sign out all sessions?</h2>
+<p>Every device signed in to your account will be signed out. Reset your password afterwards if you think someone else has it.</p>
+<form method="POST" action="{{.Action}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Sign out all sessions</button>
+</form>{{else}}<h2>{{.Heading}}</h2>
+<p>{{.Message}}</p>{{end}}
+</body>
+</html>
+`))
+
+type revokeSessionsPageData struct {
+	Action  string
+	Token   string
+	Heading string
+	Message string
+}
+
+// RevokeAllSessionsPage shows the confirmation for the "this wasn't me" link
+// from security emails. Nothing is revoked until the form is submitted.
+func (h *SessionHandler) RevokeAllSessionsPage(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		h.renderRevokeSessionsPage(c, http.StatusBadRequest, revokeSessionsPageData{Heading: "Invalid link", Message: "This link is missing its token."})
+		return
+	}
+
+	if err := h.sessionUsecase.CheckRevokeAllSessionsToken(token); err != nil {
+		h.renderRevokeSessionsPage(c, sessionErrorStatus(err), revokeSessionsFailure(err))
+		return
+	}
+
+	h.renderRevokeSessionsPage(c, http.StatusOK, revokeSessionsPageData{
+		Action: h.config.App.BaseURL + "/api/auth/revoke-sessions",
+		Token:  token,
+	})
+}
+
+// RevokeAllSessions signs the user out everywhere once the link is confirmed.
+// The confirmation form gets a page back, API clients get JSON.
+func (h *SessionHandler) RevokeAllSessions(c *gin.Context) {
+	fromForm := c.ContentType() == "application/x-www-form-urlencoded"
+
+	token := c.PostForm("token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token == "" {
+		if fromForm {
+			h.renderRevokeSessionsPage(c, http.StatusBadRequest, revokeSessionsPageData{Heading: "Invalid link", Message: "This link is missing its token."})
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", errors.New("token is required")))
+		return
+	}
+
+	if err := h.sessionUsecase.RevokeAllSessions(token); err != nil {
+		if fromForm {
+			h.renderRevokeSessionsPage(c, sessionErrorStatus(err), revokeSessionsFailure(err))
+			return
+		}
+		c.JSON(sessionErrorStatus(err), response.Error("Failed to revoke sessions", err))
+		return
+	}
+
+	if fromForm {
+		h.renderRevokeSessionsPage(c, http.StatusOK, revokeSessionsPageData{
+			Heading: "All sessions signed out",
+			Message: "Every device has been signed out of your account. Please reset your password.",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Success("All sessions revoked successfully. Please reset your password.", nil))
+}
+
+func (h *SessionHandler) renderRevokeSessionsPage(c *gin.Context, status int, data revokeSessionsPageData) {
+	// Keep the token out of caches and Referer headers
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	_ = revokeSessionsPage.Execute(c.Writer, data)
+}
+
+func revokeSessionsFailure(err error) revokeSessionsPageData {
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return revokeSessionsPageData{Heading: "Invalid link", Message: "This link is invalid, has expired or has already been used."}
+	}
+	return revokeSessionsPageData{Heading: "Something went wrong", Message: "Your sessions could not be signed out. Please try again later."}
+}
+
+// clientInfo captures the device details recorded with a new session
+func clientInfo(c *gin.Context) domain.ClientInfo {
+	return domain.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+		Locale:    requestLocale(c),
+	}
+}
+
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidToken):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
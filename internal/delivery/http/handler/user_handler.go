@@ -11,11 +11,19 @@ import (
 
 type UserHandler struct {
 	userUsecase domain.UserUsecase
+	orgUsecase  domain.OrganizationUsecase
 }
 
-func NewUserHandler(userUsecase domain.UserUsecase) *UserHandler {
+// meResponse extends the user with their organization memberships
+type meResponse struct {
+	*domain.User
+	Memberships []*domain.Membership `json:"memberships"`
+}
+
+func NewUserHandler(userUsecase domain.UserUsecase, orgUsecase domain.OrganizationUsecase) *UserHandler {
 	return &UserHandler{
 		userUsecase: userUsecase,
+		orgUsecase:  orgUsecase,
 	}
 }
 
@@ -40,7 +48,16 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response.Success("User data retrieved successfully", user))
+	memberships, err := h.orgUsecase.ListMemberships(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("Failed to get user memberships", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("User data retrieved successfully", meResponse{
+		User:        user,
+		Memberships: memberships,
+	}))
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
@@ -51,24 +68,9 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	// Check if this is a permanent deletion
-	permanent := c.DefaultQuery("permanent", "false") == "true"
-
-	// Add additional security for permanent deletion
-	if permanent {
-		// Only allow permanent deletion with specific confirmation
-		confirmation := c.GetHeader("X-Confirm-Delete")
-		if confirmation != "permanent-delete" {
-			c.JSON(http.StatusBadRequest, response.Error(
-				"Permanent deletion requires confirmation",
-				errors.New("add 'X-Confirm-Delete: permanent-delete' header for permanent deletion"),
-			))
-			return
-		}
-	}
-
-	// Delete the user
-	err := h.userUsecase.DeleteUser(userID, permanent)
+	// Accounts are only purged once the grace period ends, or by an
+	// administrator, so a stolen token cannot destroy one for good
+	err := h.userUsecase.DeleteUser(userID)
 	if err != nil {
 		switch err {
 		case domain.ErrUserNotFound:
@@ -81,10 +83,70 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	message := "User account deactivated successfully"
-	if permanent {
-		message = "User account permanently deleted"
+	c.JSON(http.StatusOK, response.Success("User account scheduled for deletion. Log in again or use the emailed link to restore it.", nil))
+}
+
+type changeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type confirmEmailChangeRequest struct {
+	OTP string `json:"otp" binding:"required,len=6"`
+}
+
+// ChangeEmail sends a confirmation to the new address. The account keeps
+// its current email until the change is confirmed.
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	var req changeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
 	}
 
-	c.JSON(http.StatusOK, response.Success(message, nil))
+	if err := h.userUsecase.RequestEmailChange(c.GetString("user_id"), req.Email, req.Password); err != nil {
+		c.JSON(emailChangeErrorStatus(err), response.Error("Failed to change email", err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.Success("Please confirm the change from your new email address", nil))
+}
+
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	var req confirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("Invalid request", err))
+		return
+	}
+
+	user, err := h.userUsecase.ConfirmEmailChange(c.GetString("user_id"), req.OTP)
+	if err != nil {
+		c.JSON(emailChangeErrorStatus(err), response.Error("Failed to confirm email change", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success("Email changed successfully", user))
+}
+
+func emailChangeErrorStatus(err error) int {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr),
+		errors.Is(err, domain.ErrInvalidOTP),
+		errors.Is(err, domain.ErrInvalidToken),
+		errors.Is(err, domain.ErrNoPendingEmail),
+		errors.Is(err, domain.ErrEmailDomainBlocked),
+		errors.Is(err, domain.ErrDisposableEmail):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrUserAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrTooManyOTPAttempts):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -1,31 +1,42 @@
 package middleware
 
 import (
+	"auth-service/internal/domain"
+	"auth-service/internal/utils"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func JWTAuth() gin.HandlerFunc {
+// JWTAuth validates the bearer token, or the session cookie when no
+// Authorization header is sent, and rejects users whose account
+// status no longer allows access, e.g. after a suspension, as well as
+// tokens whose session has been revoked.
+func JWTAuth(userUsecase domain.UserUsecase, sessionUsecase domain.SessionUsecase) gin.HandlerFunc {
     return func(c *gin.Context) {
-        authHeader := c.GetHeader("Authorization")
-        if authHeader == "" {
+        tokenString, source := "", "bearer"
+        if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+            parts := strings.Split(authHeader, " ")
+            if len(parts) != 2 || parts[0] != "Bearer" {
+                c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+                c.Abort()
+                return
+            }
+            tokenString = parts[1]
+        } else if cookie, err := c.Cookie(utils.AccessTokenCookie); err == nil && cookie != "" {
+            // Browser clients in cookie session mode
+            tokenString, source = cookie, utils.AuthSourceCookie
+        } else {
             c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
             c.Abort()
             return
         }
 
-        parts := strings.Split(authHeader, " ")
-        if len(parts) != 2 || parts[0] != "Bearer" {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-            c.Abort()
-            return
-        }
-
-        tokenString := parts[1]
         token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
             if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
                 return nil, jwt.ErrSignatureInvalid
@@ -39,14 +50,149 @@ func JWTAuth() gin.HandlerFunc {
             return
         }
 
-        if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-            c.Set("user_id", claims["id"])
-            c.Set("email", claims["email"])
-            c.Next()
-        } else {
+        claims, ok := token.Claims.(jwt.MapClaims)
+        userID, hasID := claims["id"].(string)
+        family, _ := claims["sid"].(string)
+        if !ok || !token.Valid || !hasID || family == "" {
             c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
             c.Abort()
             return
         }
+
+        user, err := userUsecase.GetUserByID(userID)
+        if err != nil {
+            if errors.Is(err, domain.ErrUserNotFound) {
+                c.JSON(http.StatusUnauthorized, gin.H{"error": "User no longer exists"})
+            } else {
+                c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify user"})
+            }
+            c.Abort()
+            return
+        }
+
+        if err := user.CheckStatus(time.Now()); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            c.Abort()
+            return
+        }
+
+        if err := sessionUsecase.ValidateSession(userID, family); err != nil {
+            switch {
+            case errors.Is(err, domain.ErrSessionEvicted):
+                c.JSON(http.StatusUnauthorized, gin.H{"error": "Session was signed out by a newer login", "code": "session_evicted"})
+            case errors.Is(err, domain.ErrSessionRevoked):
+                c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked", "code": "session_revoked"})
+            default:
+                c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify session"})
+            }
+            c.Abort()
+            return
+        }
+
+        c.Set("user_id", userID)
+        c.Set("email", claims["email"])
+        c.Set("roles", utils.ClaimStrings(claims, "roles"))
+        c.Set("permissions", utils.ClaimStrings(claims, "permissions"))
+        c.Set("groups", utils.ClaimStrings(claims, "groups"))
+        c.Set("session_family", family)
+        c.Set("auth_source", source)
+        c.Set("claims", claims)
+        c.Next()
+    }
+}
+
+// Authenticate accepts either an API key, sent as "Authorization: ApiKey
+// <key>" or in the X-API-Key header, or falls back to JWTAuth. Requests
+// authenticated by key get the same user_id context, with permissions
+// limited to the key's scopes and no roles.
+func Authenticate(
+    userUsecase domain.UserUsecase,
+    sessionUsecase domain.SessionUsecase,
+    apiKeyUsecase domain.APIKeyUsecase,
+) gin.HandlerFunc {
+    jwtAuth := JWTAuth(userUsecase, sessionUsecase)
+
+    return func(c *gin.Context) {
+        rawKey := apiKeyFromRequest(c)
+        if rawKey == "" {
+            jwtAuth(c)
+            return
+        }
+
+        identity, err := apiKeyUsecase.Authenticate(rawKey)
+        if err != nil {
+            switch {
+            case errors.Is(err, domain.ErrInvalidAPIKey),
+                errors.Is(err, domain.ErrUserNotFound):
+                c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+            case errors.Is(err, domain.ErrSuspendedUser),
+                errors.Is(err, domain.ErrInactiveUser):
+                c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            default:
+                c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify API key"})
+            }
+            c.Abort()
+            return
+        }
+
+        c.Set("user_id", identity.User.ID)
+        c.Set("email", identity.User.Email)
+        c.Set("roles", []string{})
+        c.Set("permissions", identity.Permissions)
+        c.Set("groups", identity.Groups)
+        c.Set("api_key_id", identity.Key.ID)
+        c.Next()
+    }
+}
+
+func apiKeyFromRequest(c *gin.Context) string {
+    if key := c.GetHeader("X-API-Key"); key != "" {
+        return strings.TrimSpace(key)
+    }
+
+    if scheme, key, found := strings.Cut(c.GetHeader("Authorization"), " "); found && scheme == "ApiKey" {
+        return strings.TrimSpace(key)
+    }
+
+    return ""
+}
+
+// RequirePermission allows the request only if the token carries the permission.
+// It must run after JWTAuth.
+func RequirePermission(permission string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if !contains(c.GetStringSlice("permissions"), permission) {
+            c.JSON(http.StatusForbidden, gin.H{"error": "Missing required permission: " + permission})
+            c.Abort()
+            return
+        }
+
+        c.Next()
+    }
+}
+
+// RequireRole allows the request only if the token carries one of the roles.
+// It must run after JWTAuth.
+func RequireRole(roles ...string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        granted := c.GetStringSlice("roles")
+        for _, role := range roles {
+            if contains(granted, role) {
+                c.Next()
+                return
+            }
+        }
+
+        c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient role"})
+        c.Abort()
+    }
+}
+
+func contains(values []string, target string) bool {
+    for _, value := range values {
+        if value == target {
+            return true
+        }
     }
+    return false
 }
\ No newline at end of file
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"auth-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TenantContext puts the organization selected in the token into the
+// context as org_id and org_role. The membership is re-checked so removed
+// members lose access before their token expires. Requests without an
+// org_id claim, including API key requests, pass through untouched. It
+// must run after JWTAuth or Authenticate.
+func TenantContext(orgUsecase domain.OrganizationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, _ := c.Get("claims")
+		claims, _ := raw.(jwt.MapClaims)
+		orgID, _ := claims["org_id"].(string)
+		if orgID == "" {
+			c.Next()
+			return
+		}
+
+		membership, err := orgUsecase.GetMembership(orgID, c.GetString("user_id"))
+		if err != nil {
+			if errors.Is(err, domain.ErrNotMember) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You are no longer a member of this organization"})
+			} else {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify organization membership"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("org_id", membership.OrganizationID)
+		c.Set("org_role", string(membership.Role))
+		c.Next()
+	}
+}
+
+// RequireTenant rejects requests made without an organization-scoped token.
+// It must run after TenantContext.
+func RequireTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("org_id") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Switch to an organization first"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireOrgRole allows the request only if the user holds one of the roles
+// in the current organization. It must run after TenantContext.
+func RequireOrgRole(roles ...domain.OrgRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current := domain.OrgRole(c.GetString("org_role"))
+		for _, role := range roles {
+			if current == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient organization role"})
+		c.Abort()
+	}
+}
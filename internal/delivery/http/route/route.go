@@ -1,32 +1,210 @@
 package route
 
 import (
+	"auth-service/internal/config"
 	"auth-service/internal/delivery/http/handler"
 	"auth-service/internal/delivery/http/middleware"
 	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	ratelimit "auth-service/internal/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 )
 
-func SetupRoutes(router *gin.Engine, authUsecase domain.UserUsecase) {
+// Usecases bundles the application services exposed over HTTP
+type Usecases struct {
+	Auth         domain.UserUsecase
+	RBAC         domain.RBACUsecase
+	Admin        domain.AdminUsecase
+	Organization domain.OrganizationUsecase
+	Invitation   domain.InvitationUsecase
+	Group        domain.GroupUsecase
+	Authz        domain.AuthzUsecase
+	APIKey       domain.APIKeyUsecase
+	Session      domain.SessionUsecase
+	LoginHistory domain.LoginHistoryUsecase
+	Phone        domain.PhoneUsecase
+	Profile      domain.ProfileUsecase
+	EmailQueue   domain.EmailQueueUsecase
+	Emails       *email.Renderer
+	Mailbox      *email.Mailbox
+}
+
+func SetupRoutes(router *gin.Engine, cfg *config.Config, redisClient *redis.Client, usecases Usecases) {
 	// Create handler
-	authHandler := handler.NewAuthHandler(authUsecase)
-	userHandler := handler.NewUserHandler(authUsecase)
+	authHandler := handler.NewAuthHandler(usecases.Auth, cfg)
+	userHandler := handler.NewUserHandler(usecases.Auth, usecases.Organization)
+	rbacHandler := handler.NewRBACHandler(usecases.RBAC)
+	adminHandler := handler.NewAdminHandler(usecases.Admin)
+	orgHandler := handler.NewOrganizationHandler(usecases.Organization, cfg)
+	invitationHandler := handler.NewInvitationHandler(usecases.Invitation)
+	groupHandler := handler.NewGroupHandler(usecases.Group)
+	authzHandler := handler.NewAuthzHandler(usecases.Authz)
+	apiKeyHandler := handler.NewAPIKeyHandler(usecases.APIKey)
+	sessionHandler := handler.NewSessionHandler(usecases.Session, cfg)
+	loginHistoryHandler := handler.NewLoginHistoryHandler(usecases.LoginHistory)
+	emailQueueHandler := handler.NewEmailQueueHandler(usecases.EmailQueue)
+	phoneHandler := handler.NewPhoneHandler(usecases.Phone)
+	profileHandler := handler.NewProfileHandler(usecases.Profile)
 
 	// Public routes
 	public := router.Group("/api/auth")
 	{
 		public.POST("/register", authHandler.Register)
 		public.POST("/login", authHandler.Login)
+		public.POST("/login/verify-otp", authHandler.VerifyLoginOTP)
 		public.POST("/verify-otp", authHandler.VerifyOTP)
 		public.POST("/resend-otp", authHandler.ResendOTP)
+		// Rate limited per client IP to slow down enumeration of usernames
+		public.GET("/username-available", ratelimit.RateLimitMiddleware(redisClient, cfg.Registration.UsernameCheckLimit, cfg.Registration.UsernameCheckWindow), authHandler.CheckUsername)
+		public.GET("/verify-email", authHandler.VerifyEmail)
+		public.GET("/confirm-email-change", authHandler.ConfirmEmailChange)
+		public.POST("/reset-password", authHandler.ResetPassword)
+		public.GET("/restore-account", authHandler.RestoreAccount)
+		public.GET("/revoke-sessions", sessionHandler.RevokeAllSessionsPage)
+		public.POST("/revoke-sessions", sessionHandler.RevokeAllSessions)
 	}
 
-	// Protected routes example
+	// Invitation acceptance, authorized by the emailed token
+	invitations := router.Group("/api/invitations")
+	{
+		invitations.GET("/accept", invitationHandler.GetInvitation)
+		invitations.POST("/accept", invitationHandler.AcceptInvitation)
+	}
+
+	// Protected routes, reachable with a token or an API key
 	protected := router.Group("/api")
-	protected.Use(middleware.JWTAuth())
+	protected.Use(
+		middleware.Authenticate(usecases.Auth, usecases.Session, usecases.APIKey),
+		middleware.CSRFProtect(),
+	)
 	{
 		protected.GET("/me", userHandler.GetMe)
+		protected.GET("/me/groups", groupHandler.GetMyGroups)
+
+		protected.GET("/orgs", orgHandler.ListMemberships)
+		protected.POST("/orgs", orgHandler.CreateOrganization)
+	}
+
+	// Account and credential management requires an interactive login, so a
+	// leaked API key cannot mint tokens, new keys or delete the account
+	account := router.Group("/api")
+	account.Use(
+		middleware.JWTAuth(usecases.Auth, usecases.Session),
+		middleware.CSRFProtect(),
+	)
+	{
+		account.POST("/auth/logout", sessionHandler.Logout)
+		account.PATCH("/me", profileHandler.UpdateProfile)
+		account.DELETE("/me", userHandler.DeleteUser)
+		account.POST("/me/email", userHandler.ChangeEmail)
+		account.POST("/me/email/verify", userHandler.ConfirmEmailChange)
+		account.GET("/me/api-keys", apiKeyHandler.ListAPIKeys)
+		account.POST("/me/api-keys", apiKeyHandler.CreateAPIKey)
+		account.DELETE("/me/api-keys/:id", apiKeyHandler.DeleteAPIKey)
+		account.GET("/me/sessions", sessionHandler.ListSessions)
+		account.DELETE("/me/sessions/:id", sessionHandler.RevokeSession)
+		account.GET("/me/login-history", loginHistoryHandler.ListLoginHistory)
+		account.POST("/me/phone", phoneHandler.StartPhoneVerification)
+		account.POST("/me/phone/verify", phoneHandler.ConfirmPhoneVerification)
+		account.DELETE("/me/phone", phoneHandler.RemovePhoneNumber)
+
+		account.POST("/orgs/:id/switch", orgHandler.SwitchOrganization)
+	}
+
+	// Routes scoped to the organization selected in the token
+	tenant := router.Group("/api/org")
+	tenant.Use(
+		middleware.JWTAuth(usecases.Auth, usecases.Session),
+		middleware.CSRFProtect(),
+		middleware.TenantContext(usecases.Organization),
+		middleware.RequireTenant(),
+	)
+	{
+		tenant.GET("", orgHandler.GetCurrentOrganization)
+		tenant.GET("/members", orgHandler.ListMembers)
+		tenant.POST("/members", middleware.RequireOrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), orgHandler.AddMember)
+		tenant.PATCH("/members/:userId", middleware.RequireOrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), orgHandler.UpdateMember)
+		tenant.DELETE("/members/:userId", orgHandler.RemoveMember)
+		tenant.GET("/invitations", middleware.RequireOrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), invitationHandler.ListInvitations)
+		tenant.POST("/invitations", middleware.RequireOrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), invitationHandler.CreateInvitation)
+		tenant.DELETE("/invitations/:id", middleware.RequireOrgRole(domain.OrgRoleOwner, domain.OrgRoleAdmin), invitationHandler.RevokeInvitation)
+	}
+
+	// Policy decisions for this and other services
+	authz := router.Group("/api/authz")
+	authz.Use(
+		middleware.Authenticate(usecases.Auth, usecases.Session, usecases.APIKey),
+		middleware.CSRFProtect(),
+		middleware.TenantContext(usecases.Organization),
+	)
+	{
+		authz.POST("/check", authzHandler.Check)
+	}
+
+	// Administration routes
+	admin := router.Group("/api/admin")
+	admin.Use(
+		middleware.Authenticate(usecases.Auth, usecases.Session, usecases.APIKey),
+		middleware.CSRFProtect(),
+	)
+	{
+		admin.GET("/roles", middleware.RequirePermission("roles:read"), rbacHandler.ListRoles)
+		admin.POST("/roles", middleware.RequirePermission("roles:write"), rbacHandler.CreateRole)
+		admin.POST("/roles/:name/permissions", middleware.RequirePermission("roles:write"), rbacHandler.GrantPermission)
+		admin.DELETE("/roles/:name/permissions/:permission", middleware.RequirePermission("roles:write"), rbacHandler.RevokePermission)
+		admin.GET("/permissions", middleware.RequirePermission("roles:read"), rbacHandler.ListPermissions)
+		admin.POST("/permissions", middleware.RequirePermission("roles:write"), rbacHandler.CreatePermission)
+		admin.GET("/users/:id/roles", middleware.RequirePermission("roles:read"), rbacHandler.GetUserRoles)
+		admin.POST("/users/:id/roles", middleware.RequirePermission("roles:write"), rbacHandler.AssignRole)
+		admin.DELETE("/users/:id/roles/:role", middleware.RequirePermission("roles:write"), rbacHandler.RevokeRole)
+		admin.GET("/users/:id/groups", middleware.RequirePermission("groups:read"), groupHandler.GetUserGroups)
+
+		admin.GET("/groups", middleware.RequirePermission("groups:read"), groupHandler.ListGroups)
+		admin.POST("/groups", middleware.RequirePermission("groups:write"), groupHandler.CreateGroup)
+		admin.GET("/groups/:id", middleware.RequirePermission("groups:read"), groupHandler.GetGroup)
+		admin.DELETE("/groups/:id", middleware.RequirePermission("groups:write"), groupHandler.DeleteGroup)
+		admin.POST("/groups/:id/members", middleware.RequirePermission("groups:write"), groupHandler.AddMember)
+		admin.DELETE("/groups/:id/members/:userId", middleware.RequirePermission("groups:write"), groupHandler.RemoveMember)
+		admin.POST("/groups/:id/children", middleware.RequirePermission("groups:write"), groupHandler.AddChild)
+		admin.DELETE("/groups/:id/children/:childId", middleware.RequirePermission("groups:write"), groupHandler.RemoveChild)
+
+		admin.GET("/emails/dead-letters", middleware.RequirePermission("emails:read"), emailQueueHandler.ListDeadLetters)
+		admin.GET("/emails/:id", middleware.RequirePermission("emails:read"), emailQueueHandler.GetEmail)
+		admin.POST("/emails/:id/replay", middleware.RequirePermission("emails:write"), emailQueueHandler.ReplayEmail)
+
+		admin.GET("/user-attributes/schema", middleware.RequirePermission("attributes:read"), profileHandler.GetAttributeSchema)
+		admin.PUT("/user-attributes/schema", middleware.RequirePermission("attributes:write"), profileHandler.SetAttributeSchema)
+	}
+
+	// User management, restricted to administrators
+	adminUsers := admin.Group("/users")
+	adminUsers.Use(middleware.RequireRole(domain.RoleAdmin))
+	{
+		adminUsers.GET("", adminHandler.ListUsers)
+		adminUsers.GET("/:id", adminHandler.GetUser)
+		adminUsers.POST("/:id/activate", adminHandler.ActivateUser)
+		adminUsers.POST("/:id/deactivate", adminHandler.DeactivateUser)
+		adminUsers.POST("/:id/suspend", adminHandler.SuspendUser)
+		adminUsers.POST("/:id/force-password-reset", adminHandler.ForcePasswordReset)
+		adminUsers.DELETE("/:id", adminHandler.DeleteUser)
+	}
+
+	// Development tools, never exposed outside development and test
+	if cfg.App.DevTools {
+		dev := router.Group("/dev")
+
+		if usecases.Emails != nil {
+			emailPreviewHandler := handler.NewEmailPreviewHandler(usecases.Emails)
+			dev.GET("/emails", emailPreviewHandler.ListTemplates)
+			dev.GET("/emails/:name", emailPreviewHandler.PreviewTemplate)
+		}
 
+		if usecases.Mailbox != nil {
+			mailboxHandler := handler.NewDevMailboxHandler(usecases.Mailbox)
+			dev.GET("/mailbox", mailboxHandler.ListMessages)
+			dev.DELETE("/mailbox", mailboxHandler.ClearMessages)
+		}
 	}
 }
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+type APIKeyKind string
+
+const (
+	// APIKeyPersonal keys act for the user who created them and always expire
+	APIKeyPersonal APIKeyKind = "personal"
+	// APIKeyService keys are meant for other services and may be created by
+	// administrators without an expiry
+	APIKeyService APIKeyKind = "service"
+)
+
+func (k APIKeyKind) IsValid() bool {
+	return k == APIKeyPersonal || k == APIKeyService
+}
+
+// APIKey grants programmatic access on behalf of a user, limited to its
+// scopes. The secret itself is only returned once, at creation.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name"`
+	Kind       APIKeyKind `json:"kind"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// APIKeyIdentity is the user an authenticated key acts as, with the
+// permissions the key's scopes leave them
+type APIKeyIdentity struct {
+	Key         *APIKey
+	User        *User
+	Permissions []string
+	Groups      []string
+}
+
+type APIKeyRepository interface {
+	Create(key *APIKey) error
+	GetByPrefix(prefix string) (*APIKey, error)
+	ListByUser(userID string) ([]*APIKey, error)
+	Delete(userID, id string) error
+	TouchLastUsed(id string) error
+}
+
+type APIKeyUsecase interface {
+	CreateAPIKey(userID string, key *APIKey) (string, error)
+	ListAPIKeys(userID string) ([]*APIKey, error)
+	DeleteAPIKey(userID, id string) error
+	Authenticate(rawKey string) (*APIKeyIdentity, error)
+}
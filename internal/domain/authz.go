@@ -0,0 +1,31 @@
+package domain
+
+// Subject is the principal asking to perform an action
+type Subject struct {
+	ID          string                 `json:"id"`
+	Roles       []string               `json:"roles,omitempty"`
+	Permissions []string               `json:"permissions,omitempty"`
+	Groups      []string               `json:"groups,omitempty"`
+	OrgID       string                 `json:"orgId,omitempty"`
+	OrgRole     string                 `json:"orgRole,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Resource is the object an action targets. Attributes carry whatever the
+// calling service knows about it, such as owner_id or org_id.
+type Resource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Decision is the outcome of a policy evaluation
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule,omitempty"`
+	Cached  bool   `json:"cached"`
+}
+
+type AuthzUsecase interface {
+	Authorize(subject *Subject, action string, resource *Resource) (*Decision, error)
+}
@@ -8,10 +8,81 @@ var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInactiveUser       = errors.New("user is not active")
+	ErrSuspendedUser      = errors.New("user is suspended")
+	ErrInvalidUserStatus  = errors.New("invalid user status")
+	ErrInvalidTransition  = errors.New("invalid user status transition")
+	ErrRestoreExpired     = errors.New("account deletion grace period has expired")
+	ErrNoPendingEmail     = errors.New("no email change is pending")
+	ErrEmailDomainBlocked = errors.New("email domain is not allowed")
+	ErrDisposableEmail    = errors.New("disposable email addresses are not allowed")
+	ErrUsernameTaken      = errors.New("username already in use")
+
+	// Profile errors
+	ErrAttributeSchemaNotFound = errors.New("no attribute schema has been defined")
+	ErrInvalidAttributeSchema  = errors.New("invalid attribute schema")
+
+	// Password reset errors
+	ErrPasswordResetRequired = errors.New("password reset required")
+	ErrInvalidResetCode      = errors.New("invalid or expired reset code")
+
+	// Authorization errors
+	ErrForbidden               = errors.New("forbidden")
+	ErrRoleNotFound            = errors.New("role not found")
+	ErrRoleNotAssigned         = errors.New("user does not have role")
+	ErrRoleAlreadyExists       = errors.New("role already exists")
+	ErrPermissionNotFound      = errors.New("permission not found")
+	ErrPermissionAlreadyExists = errors.New("permission already exists")
+
+	// Organization errors
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrSlugTaken            = errors.New("organization slug already taken")
+	ErrNotMember            = errors.New("user is not a member of the organization")
+	ErrAlreadyMember        = errors.New("user is already a member of the organization")
+	ErrInvalidOrgRole       = errors.New("invalid organization role")
+	ErrLastOwner            = errors.New("organization must keep at least one owner")
+	ErrNoTenant             = errors.New("no organization selected")
+
+	// Invitation errors
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationInvalid  = errors.New("invitation is no longer valid")
+	ErrRegistrationClosed = errors.New("registration is by invitation only")
+
+	// Group errors
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrGroupAlreadyExists = errors.New("group already exists")
+	ErrGroupCycle         = errors.New("group nesting would create a cycle")
+	ErrAlreadyInGroup     = errors.New("already a member of the group")
+	ErrNotInGroup         = errors.New("not a member of the group")
+
+	// API key errors
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid or expired API key")
+
+	// Session errors
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrSessionEvicted      = errors.New("session was signed out by a newer login")
+	ErrSessionLimitReached = errors.New("maximum number of concurrent sessions reached; log out on another device or use the sign-out link emailed to you")
+
+	// Login history errors
+	ErrLoginEventNotFound = errors.New("login event not found")
+
+	// Email queue errors
+	ErrEmailNotFound = errors.New("email not found")
+	ErrEmailNotDead  = errors.New("email is not a dead letter")
+
+	// Phone errors
+	ErrInvalidPhoneNumber       = errors.New("phone number must be in E.164 format, such as +14155550100")
+	ErrPhoneNumberTaken         = errors.New("phone number already in use")
+	ErrInvalidChannel           = errors.New("unsupported notification channel")
+	ErrNotificationsUnavailable = errors.New("phone notifications are not configured")
+	ErrVerificationCooldown     = errors.New("a code was sent recently, please wait before requesting another")
+	ErrVerificationLimitReached = errors.New("too many codes requested today, please try again tomorrow")
 
 	// OTP errors
 	ErrInvalidOTP         = errors.New("invalid or expired OTP")
 	ErrOTPAlreadyVerified = errors.New("OTP already verified")
+	ErrTooManyOTPAttempts = errors.New("too many OTP attempts")
 
 	// Authentication errors
 	ErrUnauthorized = errors.New("unauthorized")
@@ -37,6 +108,18 @@ func (e *ValidationError) Error() string {
 	return e.Field + ": " + e.Message
 }
 
+// StepUpRequiredError is returned by a login judged too risky to complete
+// with a password alone. An OTP has been sent over Channel, either email or
+// a phone channel, and submitting it with the challenge finishes the login.
+type StepUpRequiredError struct {
+	Challenge string
+	Channel   string
+}
+
+func (e *StepUpRequiredError) Error() string {
+	return "additional verification required"
+}
+
 // NewValidationError creates a new validation error
 func NewValidationError(field, message string) error {
 	return &ValidationError{
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Group collects users and other groups. Members of a child group are
+// transitively members of every ancestor.
+type Group struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Members     []string  `json:"members,omitempty"`
+	Children    []string  `json:"children,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type GroupRepository interface {
+	Create(group *Group) error
+	GetByID(id string) (*Group, error)
+	List() ([]*Group, error)
+	Delete(id string) error
+	AddMember(groupID, userID string) error
+	RemoveMember(groupID, userID string) error
+	AddChild(parentID, childID string) error
+	RemoveChild(parentID, childID string) error
+	GetUserGroups(userID string) ([]string, error)
+}
+
+type GroupUsecase interface {
+	CreateGroup(group *Group) error
+	GetGroup(id string) (*Group, error)
+	ListGroups() ([]*Group, error)
+	DeleteGroup(id string) error
+	AddMember(groupID, userID string) error
+	RemoveMember(groupID, userID string) error
+	AddChild(parentID, childID string) error
+	RemoveChild(parentID, childID string) error
+	GetUserGroups(userID string) ([]string, error)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+type Invitation struct {
+	ID               string     `json:"id"`
+	OrganizationID   string     `json:"organizationId"`
+	OrganizationName string     `json:"organizationName,omitempty"`
+	Email            string     `json:"email"`
+	Role             OrgRole    `json:"role"`
+	InvitedBy        string     `json:"invitedBy,omitempty"`
+	ExpiresAt        time.Time  `json:"expiresAt"`
+	AcceptedAt       *time.Time `json:"acceptedAt,omitempty"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+// Status derives the invitation's state from its timestamps
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+type InvitationRepository interface {
+	Create(invitation *Invitation) error
+	GetByID(id string) (*Invitation, error)
+	ListByOrganization(orgID string) ([]*Invitation, error)
+	RevokePending(orgID, email string) error
+	Revoke(orgID, id string) error
+	Claim(invitation *Invitation) error
+	Release(id string) error
+}
+
+type InvitationUsecase interface {
+	CreateInvitation(orgID, actorID, email string, role OrgRole) (*Invitation, error)
+	ListInvitations(orgID string) ([]*Invitation, error)
+	RevokeInvitation(orgID, id string) error
+	GetInvitation(token string) (*Invitation, error)
+	AcceptInvitation(token, name, password string, client ClientInfo) (string, error)
+}
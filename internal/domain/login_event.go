@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Login methods recorded in the login history
+const (
+	LoginMethodPassword   = "password"
+	LoginMethodInvitation = "invitation"
+	LoginMethodOTPStepUp  = "otp_step_up"
+)
+
+// LoginEvent is one entry in the append-only login history
+type LoginEvent struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"userId,omitempty"`
+	Email             string    `json:"email"`
+	Method            string    `json:"method"`
+	Success           bool      `json:"success"`
+	FailureReason     string    `json:"failureReason,omitempty"`
+	IPAddress         string    `json:"ipAddress"`
+	UserAgent         string    `json:"userAgent"`
+	DeviceFingerprint string    `json:"-"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type LoginEventPage struct {
+	Events   []*LoginEvent `json:"events"`
+	Total    int           `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"pageSize"`
+}
+
+type LoginHistoryRepository interface {
+	Record(event *LoginEvent) error
+	ListByUser(userID string, limit, offset int) ([]*LoginEvent, int, error)
+	LastSuccess(userID string) (*LoginEvent, error)
+	// DeviceSeen reports whether the user has signed in successfully before,
+	// and whether any of those sign-ins came from the device
+	DeviceSeen(userID, fingerprint string) (seen bool, hasHistory bool, err error)
+}
+
+type LoginHistoryUsecase interface {
+	ListLoginHistory(userID string, page, pageSize int) (*LoginEventPage, error)
+}
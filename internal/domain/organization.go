@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleMember OrgRole = "member"
+)
+
+func (r OrgRole) IsValid() bool {
+	switch r {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return true
+	}
+	return false
+}
+
+// CanManageMembers reports whether the role may add, change or remove members
+func (r OrgRole) CanManageMembers() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Membership links a user to an organization. The organization and user
+// fields are denormalized for listings.
+type Membership struct {
+	OrganizationID   string    `json:"organizationId"`
+	OrganizationName string    `json:"organizationName,omitempty"`
+	OrganizationSlug string    `json:"organizationSlug,omitempty"`
+	UserID           string    `json:"userId"`
+	UserEmail        string    `json:"userEmail,omitempty"`
+	UserName         string    `json:"userName,omitempty"`
+	Role             OrgRole   `json:"role"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type OrganizationRepository interface {
+	Create(org *Organization, ownerID string) error
+	GetByID(id string) (*Organization, error)
+	GetMembership(orgID, userID string) (*Membership, error)
+	ListMembershipsByUser(userID string) ([]*Membership, error)
+	ListMembers(orgID string) ([]*Membership, error)
+	AddMember(orgID, userID string, role OrgRole) error
+	UpdateMemberRole(orgID, userID string, role OrgRole) error
+	RemoveMember(orgID, userID string) error
+	CountOwners(orgID string) (int, error)
+}
+
+type OrganizationUsecase interface {
+	CreateOrganization(userID string, org *Organization) error
+	GetOrganization(orgID string) (*Organization, error)
+	ListMemberships(userID string) ([]*Membership, error)
+	GetMembership(orgID, userID string) (*Membership, error)
+	ListMembers(orgID string) ([]*Membership, error)
+	AddMember(orgID, actorID, email string, role OrgRole) error
+	UpdateMemberRole(orgID, actorID, userID string, role OrgRole) error
+	RemoveMember(orgID, actorID, userID string) error
+	SwitchOrganization(userID, orgID, sessionFamily string) (string, error)
+}
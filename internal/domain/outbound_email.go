@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+type OutboundEmailStatus string
+
+const (
+	OutboundEmailPending OutboundEmailStatus = "pending"
+	OutboundEmailSending OutboundEmailStatus = "sending"
+	OutboundEmailSent    OutboundEmailStatus = "sent"
+	OutboundEmailDead    OutboundEmailStatus = "dead"
+)
+
+// OutboundEmail is a message waiting in, or retired from, the delivery
+// queue. The payload holds the encoded message and is dropped once it has
+// been sent, so one-time codes do not outlive their delivery.
+type OutboundEmail struct {
+	ID            string              `json:"id"`
+	Recipients    string              `json:"recipients"`
+	Subject       string              `json:"subject"`
+	Payload       []byte              `json:"-"`
+	Status        OutboundEmailStatus `json:"status"`
+	Attempts      int                 `json:"attempts"`
+	LastError     string              `json:"lastError,omitempty"`
+	NextAttemptAt time.Time           `json:"nextAttemptAt"`
+	SentAt        *time.Time          `json:"sentAt,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+}
+
+type OutboundEmailPage struct {
+	Emails   []*OutboundEmail `json:"emails"`
+	Total    int              `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"pageSize"`
+}
+
+type EmailQueueRepository interface {
+	Enqueue(email *OutboundEmail) error
+	// ClaimDue leases up to limit due messages to the caller, including any
+	// whose earlier lease ran out before they were settled
+	ClaimDue(limit int, lease time.Duration) ([]*OutboundEmail, error)
+	MarkSent(id string) error
+	// MarkFailed records a failed attempt. The message is retried at next,
+	// or dead lettered when dead is set.
+	MarkFailed(id, lastError string, next time.Time, dead bool) error
+	GetByID(id string) (*OutboundEmail, error)
+	ListByStatus(status OutboundEmailStatus, limit, offset int) ([]*OutboundEmail, int, error)
+	// Replay queues a dead letter for immediate delivery with fresh retries
+	Replay(id string) error
+}
+
+type EmailQueueUsecase interface {
+	ListDeadLetters(page, pageSize int) (*OutboundEmailPage, error)
+	GetEmail(id string) (*OutboundEmail, error)
+	ReplayEmail(id string) error
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// e164Pattern matches a number in E.164 form: a plus sign, a country code
+// that does not start with zero and at most 15 digits in total
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// phoneSeparators may appear in numbers as people write them
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// NormalizePhoneNumber strips common separators from the number and checks
+// that what remains is in E.164 form
+func NormalizePhoneNumber(phoneNumber string) (string, error) {
+	normalized := phoneSeparators.Replace(strings.TrimSpace(phoneNumber))
+	if !e164Pattern.MatchString(normalized) {
+		return "", ErrInvalidPhoneNumber
+	}
+	return normalized, nil
+}
+
+type PhoneUsecase interface {
+	// StartPhoneVerification sends a code to the number over the channel.
+	// The number replaces the user's current one once the code is confirmed.
+	StartPhoneVerification(userID, phoneNumber, channel string) error
+	ConfirmPhoneVerification(userID, otp string) (*User, error)
+	RemovePhoneNumber(userID string) error
+}
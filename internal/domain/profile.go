@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProfileUpdate holds the profile fields to change. Nil fields are left
+// alone and an empty username removes it. Attributes are merged into the
+// user's custom attributes, with null values removing keys.
+type ProfileUpdate struct {
+	Name       *string
+	Username   *string
+	Locale     *string
+	Timezone   *string
+	AvatarURL  *string
+	Attributes map[string]interface{}
+}
+
+// AttributeSchema is a JSON schema that custom user attributes must
+// satisfy. Each change is stored as a new version.
+type AttributeSchema struct {
+	Version   int             `json:"version"`
+	Schema    json.RawMessage `json:"schema"`
+	CreatedBy string          `json:"createdBy,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+type AttributeSchemaRepository interface {
+	// GetCurrent returns the newest schema version
+	GetCurrent() (*AttributeSchema, error)
+	Create(schema *AttributeSchema) error
+}
+
+type ProfileUsecase interface {
+	UpdateProfile(userID string, update ProfileUpdate) (*User, error)
+	GetAttributeSchema() (*AttributeSchema, error)
+	// SetAttributeSchema replaces the schema. Stored attributes are not
+	// checked against it until the user next updates them.
+	SetAttributeSchema(actorID string, schema json.RawMessage) (*AttributeSchema, error)
+}
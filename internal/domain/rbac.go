@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+type Role struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type Permission struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type RoleRepository interface {
+	CreateRole(role *Role) error
+	GetRoleByName(name string) (*Role, error)
+	ListRoles() ([]*Role, error)
+	CreatePermission(permission *Permission) error
+	ListPermissions() ([]*Permission, error)
+	AddPermissionToRole(roleName, permissionName string) error
+	RemovePermissionFromRole(roleName, permissionName string) error
+	AssignRole(userID, roleName string) error
+	RevokeRole(userID, roleName string) error
+	GetUserRoles(userID string) ([]string, error)
+	GetUserPermissions(userID string) ([]string, error)
+}
+
+type RBACUsecase interface {
+	CreateRole(role *Role) error
+	ListRoles() ([]*Role, error)
+	CreatePermission(permission *Permission) error
+	ListPermissions() ([]*Permission, error)
+	GrantPermission(roleName, permissionName string) error
+	RevokePermission(roleName, permissionName string) error
+	AssignRole(userID, roleName string) error
+	RevokeRole(userID, roleName string) error
+	GetUserRoles(userID string) ([]string, error)
+	GetUserPermissions(userID string) ([]string, error)
+}
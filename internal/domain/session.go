@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// ClientInfo describes where a login came from. Locale is the language the
+// client prefers, used for emails when the user has not chosen one.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+	Locale    string
+}
+
+// Session records one login. Every token issued for it, including tokens
+// from switching organizations, shares the session's token family.
+type Session struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"userId"`
+	TokenFamily string     `json:"-"`
+	UserAgent   string     `json:"userAgent"`
+	IPAddress   string     `json:"ipAddress"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastSeenAt  time.Time  `json:"lastSeenAt"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	Current     bool       `json:"current"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+type SessionRepository interface {
+	Create(session *Session) error
+	GetByFamily(family string) (*Session, error)
+	ListActiveByUser(userID string) ([]*Session, error)
+	Extend(family string, expiresAt time.Time) error
+	TouchLastSeen(family string) error
+	Revoke(userID, id string) (string, error)
+	RevokeByFamily(family string) error
+	RevokeAllByUser(userID string) ([]string, error)
+}
+
+type SessionUsecase interface {
+	ListSessions(userID, currentFamily string) ([]*Session, error)
+	RevokeSession(userID, id string) error
+	Logout(userID, family string) error
+	ValidateSession(userID, family string) error
+	CheckRevokeAllSessionsToken(token string) error
+	RevokeAllSessions(token string) error
+}
@@ -3,30 +3,137 @@ package domain
 import "time"
 
 type User struct {
-	ID        string     `json:"id"`
-	Email     string     `json:"email"`
-	Name      string     `json:"name"`
-	Password  string     `json:"-"`
-	IsActive  bool       `json:"isActive"`
-	IsDeleted bool       `json:"isDeleted,omitempty"`
-	DeletedAt *time.Time `json:"deletedAt,omitempty"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
+	ID                    string                 `json:"id"`
+	Email                 string                 `json:"email"`
+	PendingEmail          string                 `json:"pendingEmail,omitempty"`
+	NormalizedEmail       string                 `json:"-"`
+	Username              string                 `json:"username,omitempty"`
+	Name                  string                 `json:"name"`
+	Locale                string                 `json:"locale,omitempty"`
+	Timezone              string                 `json:"timezone,omitempty"`
+	AvatarURL             string                 `json:"avatarUrl,omitempty"`
+	PhoneNumber           string                 `json:"phoneNumber,omitempty"`
+	PhoneVerifiedAt       *time.Time             `json:"phoneVerifiedAt,omitempty"`
+	EmailVerifiedAt       *time.Time             `json:"emailVerifiedAt,omitempty"`
+	Password              string                 `json:"-"`
+	IsActive              bool                   `json:"isActive"`
+	Status                UserStatus             `json:"status"`
+	SuspensionReason      string                 `json:"suspensionReason,omitempty"`
+	SuspendedUntil        *time.Time             `json:"suspendedUntil,omitempty"`
+	PasswordResetRequired bool                   `json:"passwordResetRequired,omitempty"`
+	IsDeleted             bool                   `json:"isDeleted,omitempty"`
+	DeletedAt             *time.Time             `json:"deletedAt,omitempty"`
+	DeletedBy             string                 `json:"deletedBy,omitempty"`
+	StatusBeforeDelete    UserStatus             `json:"statusBeforeDelete,omitempty"`
+	Attributes            map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt             time.Time              `json:"createdAt"`
+	UpdatedAt             time.Time              `json:"updatedAt"`
 }
+
+// UserFilter narrows down user listings. Zero values are ignored.
+type UserFilter struct {
+	Email          string
+	Name           string
+	Status         UserStatus
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	IncludeDeleted bool
+	Page           int
+	PageSize       int
+}
+
+type UserPage struct {
+	Users    []*User `json:"users"`
+	Total    int     `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"pageSize"`
+}
+
+// UserRepository hides soft-deleted users from every read except the
+// IncludingDeleted variants and List with IncludeDeleted set.
 type UserRepository interface {
 	Create(user *User) error
 	GetByEmail(email string) (*User, error)
+	// GetByNormalizedEmail finds the live account whose canonical address
+	// matches, whatever variant of it was registered
+	GetByNormalizedEmail(normalizedEmail string) (*User, error)
+	// GetByUsername finds the live account with the username, ignoring case
+	GetByUsername(username string) (*User, error)
 	GetByID(id string) (*User, error)
-	UpdateActive(id string, isActive bool) error
-	SoftDelete(id string) error
+	GetByEmailIncludingDeleted(email string) (*User, error)
+	GetByNormalizedEmailIncludingDeleted(normalizedEmail string) (*User, error)
+	GetByIDIncludingDeleted(id string) (*User, error)
+	List(filter UserFilter) ([]*User, int, error)
+	// MarkEmailVerified activates an account that has never been verified
+	// and records when it was. It fails with ErrOTPAlreadyVerified for any
+	// other account.
+	MarkEmailVerified(id string) error
+	GetPasswordHash(id string) (string, error)
+	UpdateStatus(id string, change StatusChange) error
+	UpdatePassword(id string, hashedPassword string) error
+	SetPasswordResetRequired(id string, required bool) error
+	// SetPhoneNumber stores a verified phone number, or removes it when empty
+	SetPhoneNumber(id, phoneNumber string) error
+	SetPendingEmail(id, email string) error
+	// UpdateProfile saves the user's name, username, locale, timezone,
+	// avatar URL and custom attributes. It fails with ErrUsernameTaken when
+	// another account holds the username.
+	UpdateProfile(user *User) error
+	// ConfirmEmailChange swaps in the pending email and its canonical form
+	// if it still matches, and returns the address it replaced. It fails
+	// with ErrUserAlreadyExists when another account took the address first.
+	ConfirmEmailChange(id, pendingEmail, normalizedEmail string) (string, error)
+	// SoftDelete records the actor who deleted the account, which is the
+	// user themselves for self-service deletion, and its status at the time
+	SoftDelete(id, actorID string) error
+	// Restore undeletes the account with the status it had before deletion
+	// and returns that status
+	Restore(id string) (UserStatus, error)
+	ListDeletedBefore(cutoff time.Time, limit int) ([]*User, error)
 	HardDelete(id string) error
+	// NormalizeEmails brings every stored canonical address in line with
+	// the given rules. Live accounts that would share a mailbox keep their
+	// current value and are returned as conflicts.
+	NormalizeEmails(normalize func(email string) string) ([]EmailConflict, error)
+}
+
+// EmailConflict is an account whose canonical address is already held by
+// another live account
+type EmailConflict struct {
+	UserID          string
+	Email           string
+	NormalizedEmail string
+	HeldBy          string
 }
 
 type UserUsecase interface {
 	Register(user *User) error
-	Login(email, password string) (string, error)
+	// Login accepts either an email address or a username as the identifier
+	Login(identifier, password string, client ClientInfo) (string, error)
+	VerifyLoginOTP(challenge, otp string, client ClientInfo) (string, error)
 	VerifyOTP(email, otp string) error
+	VerifyEmail(token string) error
+	RequestEmailChange(userID, newEmail, password string) error
+	ConfirmEmailChange(userID, otp string) (*User, error)
+	ConfirmEmailChangeLink(token string) error
 	ResendOTP(email string) error
+	// CheckUsernameAvailable reports whether the username is valid and free
+	CheckUsernameAvailable(username string) (bool, error)
+	ResetPassword(email, code, newPassword string) error
 	GetUserByID(id string) (*User, error)
-	DeleteUser(id string, permanent bool) error
+	// DeleteUser soft deletes the account. It is purged once the deletion
+	// grace period ends unless the user restores it first.
+	DeleteUser(id string) error
+	RestoreAccount(token string) error
+	PurgeDeletedAccounts() (int, error)
+}
+
+type AdminUsecase interface {
+	ListUsers(filter UserFilter) (*UserPage, error)
+	GetUser(id string, includeDeleted bool) (*User, error)
+	ActivateUser(id string) error
+	DeactivateUser(id string) error
+	SuspendUser(id string, reason string, until *time.Time) error
+	ForcePasswordReset(id string) error
+	DeleteUser(actorID, id string, permanent bool) error
 }
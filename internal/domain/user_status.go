@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+type UserStatus string
+
+const (
+	UserStatusActive      UserStatus = "active"
+	UserStatusInactive    UserStatus = "inactive"
+	UserStatusDeactivated UserStatus = "deactivated"
+	UserStatusSuspended   UserStatus = "suspended"
+	UserStatusDeleted     UserStatus = "deleted"
+)
+
+// userStatusTransitions lists the statuses reachable from each status.
+// Accounts start inactive until their email is verified and never return
+// to it. Active and suspended accounts can move between each other or be
+// deactivated by an administrator, and any account can be deleted. Deleted
+// accounts can be restored while their deletion grace period lasts.
+var userStatusTransitions = map[UserStatus][]UserStatus{
+	UserStatusInactive:    {UserStatusActive, UserStatusDeleted},
+	UserStatusActive:      {UserStatusSuspended, UserStatusDeactivated, UserStatusDeleted},
+	UserStatusDeactivated: {UserStatusActive, UserStatusDeleted},
+	UserStatusSuspended:   {UserStatusActive, UserStatusDeactivated, UserStatusDeleted},
+	UserStatusDeleted:     {UserStatusActive},
+}
+
+// AwaitingVerification reports whether the account is new and has never
+// proved its email address
+func (u *User) AwaitingVerification() bool {
+	return u.Status == UserStatusInactive && u.EmailVerifiedAt == nil
+}
+
+func ParseUserStatus(value string) (UserStatus, error) {
+	status := UserStatus(value)
+	if !status.IsValid() {
+		return "", ErrInvalidUserStatus
+	}
+	return status, nil
+}
+
+func (s UserStatus) IsValid() bool {
+	_, ok := userStatusTransitions[s]
+	return ok
+}
+
+func (s UserStatus) CanTransitionTo(next UserStatus) bool {
+	for _, allowed := range userStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange describes a status transition. Reason and Until only apply
+// to suspensions; a nil Until suspends indefinitely.
+type StatusChange struct {
+	Status UserStatus
+	Reason string
+	Until  *time.Time
+}
+
+// EffectiveStatus treats a suspension whose expiry has passed as active
+func (u *User) EffectiveStatus(now time.Time) UserStatus {
+	if u.IsDeleted {
+		return UserStatusDeleted
+	}
+	if u.Status == UserStatusSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+		return UserStatusActive
+	}
+	return u.Status
+}
+
+// CheckStatus reports why the user may not authenticate, or nil if they may
+func (u *User) CheckStatus(now time.Time) error {
+	switch u.EffectiveStatus(now) {
+	case UserStatusActive:
+		return nil
+	case UserStatusSuspended:
+		return ErrSuspendedUser
+	case UserStatusDeleted:
+		return ErrUserNotFound
+	default:
+		return ErrInactiveUser
+	}
+}
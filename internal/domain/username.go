@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// usernamePattern allows 3 to 32 letters, digits, dots, hyphens and
+// underscores, starting with a letter and ending with a letter or digit
+var usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]{1,30}[A-Za-z0-9]$`)
+
+// reservedUsernames could be mistaken for the service or its staff, or clash
+// with paths that embed a username
+var reservedUsernames = map[string]bool{
+	"abuse": true, "account": true, "accounts": true, "admin": true,
+	"administrator": true, "api": true, "auth": true, "billing": true,
+	"help": true, "hostmaster": true, "info": true, "login": true,
+	"logout": true, "me": true, "moderator": true, "noreply": true,
+	"no-reply": true, "null": true, "owner": true, "postmaster": true,
+	"register": true, "root": true, "security": true, "settings": true,
+	"signin": true, "signup": true, "staff": true, "support": true,
+	"sysadmin": true, "system": true, "undefined": true, "webmaster": true,
+}
+
+// NormalizeUsername trims the username and checks the charset and reserved
+// words. Case is kept for display; uniqueness ignores it.
+func NormalizeUsername(username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if !usernamePattern.MatchString(username) {
+		return "", NewValidationError("username", "username must be 3 to 32 letters, digits, dots, hyphens or underscores, starting with a letter and ending with a letter or digit")
+	}
+	if strings.Contains(username, "..") || strings.Contains(username, "--") || strings.Contains(username, "__") {
+		return "", NewValidationError("username", "username must not repeat separators")
+	}
+	if reservedUsernames[strings.ToLower(username)] {
+		return "", NewValidationError("username", "username is reserved")
+	}
+	return username, nil
+}
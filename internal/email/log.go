@@ -0,0 +1,36 @@
+package email
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+)
+
+// LogSender prints messages to stdout instead of sending them, for local
+// development
+type LogSender struct {
+	logger *log.Logger
+}
+
+func NewLogSender() *LogSender {
+	return &LogSender{
+		logger: log.New(os.Stdout, "[DEV EMAIL] ", log.LstdFlags),
+	}
+}
+
+func (s *LogSender) Send(_ context.Context, msg Message) error {
+	s.logger.Printf("\n==================================")
+	s.logger.Printf("🚀 New Email")
+	s.logger.Printf("📧 To: %s", strings.Join(msg.Recipients(), ", "))
+	s.logger.Printf("📝 Subject: %s", msg.Subject)
+	if msg.Locale != "" {
+		s.logger.Printf("🌐 Locale: %s", msg.Locale)
+	}
+	for _, attachment := range msg.Attachments {
+		s.logger.Printf("📎 Attachment: %s", attachment.Filename)
+	}
+	s.logger.Printf("\n%s", msg.Text)
+	s.logger.Printf("==================================\n")
+	return nil
+}
@@ -0,0 +1,158 @@
+package email
+
+import (
+	"context"
+	"net/mail"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// mailboxCapacity bounds the mailbox, dropping the oldest mail beyond it
+const mailboxCapacity = 1000
+
+var (
+	// Codes are printed on a line of their own, so dates and other numbers
+	// in the surrounding text are not mistaken for them
+	mailboxCodePattern = regexp.MustCompile(`(?m)^[ \t]*([0-9]{4,10})[ \t]*$`)
+	mailboxLinkPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+)
+
+// CapturedMessage is a message caught by the mailbox, with the codes and
+// links found in its text picked out for test clients
+type CapturedMessage struct {
+	ID         string    `json:"id"`
+	ReceivedAt time.Time `json:"receivedAt"`
+	From       string    `json:"from"`
+	To         []string  `json:"to"`
+	Cc         []string  `json:"cc,omitempty"`
+	Subject    string    `json:"subject"`
+	Locale     string    `json:"locale,omitempty"`
+	Text       string    `json:"text"`
+	HTML       string    `json:"html,omitempty"`
+	Codes      []string  `json:"codes"`
+	Links      []string  `json:"links"`
+
+	recipients []string
+}
+
+// Mailbox keeps a copy of every message sent through it before passing it
+// on, so end-to-end tests can read OTPs and links back over HTTP. It is
+// for development and test environments only.
+type Mailbox struct {
+	next     Sender
+	mu       sync.Mutex
+	messages []CapturedMessage
+}
+
+func NewMailbox(next Sender) *Mailbox {
+	return &Mailbox{
+		next: next,
+	}
+}
+
+func (b *Mailbox) Send(ctx context.Context, msg Message) error {
+	captured := CapturedMessage{
+		ID:         uuid.New().String(),
+		ReceivedAt: time.Now(),
+		From:       msg.From,
+		To:         msg.To,
+		Cc:         msg.Cc,
+		Subject:    msg.Subject,
+		Locale:     msg.Locale,
+		Text:       msg.Text,
+		HTML:       msg.HTML,
+		Codes:      extractCodes(msg.Text),
+		Links:      extractLinks(msg.Text),
+	}
+	for _, recipient := range msg.Recipients() {
+		captured.recipients = append(captured.recipients, mailboxAddress(recipient))
+	}
+
+	b.mu.Lock()
+	b.messages = append(b.messages, captured)
+	if len(b.messages) > mailboxCapacity {
+		b.messages = b.messages[len(b.messages)-mailboxCapacity:]
+	}
+	b.mu.Unlock()
+
+	return b.next.Send(ctx, msg)
+}
+
+// Messages returns the mail sent to the address, newest first. An empty
+// address returns everything.
+func (b *Mailbox) Messages(to string) []CapturedMessage {
+	to = mailboxAddress(to)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	messages := []CapturedMessage{}
+	for i := len(b.messages) - 1; i >= 0; i-- {
+		if to == "" || b.messages[i].sentTo(to) {
+			messages = append(messages, b.messages[i])
+		}
+	}
+	return messages
+}
+
+// Clear deletes the mail sent to the address, or all mail when it is
+// empty, and reports how many messages were removed
+func (b *Mailbox) Clear(to string) int {
+	to = mailboxAddress(to)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	kept := b.messages[:0]
+	for _, msg := range b.messages {
+		if to != "" && !msg.sentTo(to) {
+			kept = append(kept, msg)
+		}
+	}
+
+	removed := len(b.messages) - len(kept)
+	b.messages = kept
+	return removed
+}
+
+func (m CapturedMessage) sentTo(address string) bool {
+	for _, recipient := range m.recipients {
+		if recipient == address {
+			return true
+		}
+	}
+	return false
+}
+
+// mailboxAddress reduces "Name <user@example.com>" to a lowercase address
+func mailboxAddress(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+func extractCodes(text string) []string {
+	codes := []string{}
+	for _, match := range mailboxCodePattern.FindAllStringSubmatch(text, -1) {
+		codes = append(codes, match[1])
+	}
+	return codes
+}
+
+func extractLinks(text string) []string {
+	links := []string{}
+	seen := map[string]bool{}
+	for _, link := range mailboxLinkPattern.FindAllString(text, -1) {
+		link = strings.TrimRight(link, ".,;:!?)")
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
@@ -0,0 +1,63 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// mboxSender appends messages to a local mbox file, for inspecting mail in
+// a desktop client without an SMTP server
+type mboxSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+// fromLine matches body lines that must be escaped, including ones escaped
+// already (mboxrd)
+var fromLine = regexp.MustCompile(`(?m)^(>*From )`)
+
+func newMboxSender(cfg ProviderConfig) (Sender, error) {
+	if cfg.FilePath == "" {
+		return nil, errors.New("email file path is required")
+	}
+
+	return &mboxSender{path: cfg.FilePath}, nil
+}
+
+func (s *mboxSender) Send(_ context.Context, msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	sender := "MAILER-DAEMON"
+	if address, err := mail.ParseAddress(msg.From); err == nil {
+		sender = address.Address
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From %s %s\n", sender, time.Now().UTC().Format(time.ANSIC))
+	buf.Write(fromLine.ReplaceAll(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), []byte(">$1")))
+	buf.WriteString("\n\n")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mbox: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write mbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is an outgoing email. Rendering a template fills in the subject,
+// bodies and locale; the caller adds recipients and anything else.
+type Message struct {
+	From        string            `json:"from,omitempty"`
+	To          []string          `json:"to,omitempty"`
+	Cc          []string          `json:"cc,omitempty"`
+	Bcc         []string          `json:"bcc,omitempty"`
+	ReplyTo     string            `json:"replyTo,omitempty"`
+	Subject     string            `json:"subject"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Locale      string            `json:"locale,omitempty"`
+}
+
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+// reservedHeaders are written by the encoder and may not be set directly
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Reply-To":                  true,
+	"Subject":                   true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// Recipients returns every envelope recipient, including Bcc
+func (m Message) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+	return append(recipients, m.Bcc...)
+}
+
+// Validate checks the addresses and headers before the message is sent
+func (m Message) Validate() error {
+	if _, err := mail.ParseAddress(m.From); err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	if len(m.Recipients()) == 0 {
+		return errors.New("message has no recipients")
+	}
+	for _, address := range m.Recipients() {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("invalid recipient address %q: %w", address, err)
+		}
+	}
+	if m.ReplyTo != "" {
+		if _, err := mail.ParseAddress(m.ReplyTo); err != nil {
+			return fmt.Errorf("invalid reply-to address: %w", err)
+		}
+	}
+	if m.Text == "" && m.HTML == "" {
+		return errors.New("message has no body")
+	}
+	for name, value := range m.Headers {
+		if reservedHeaders[textproto.CanonicalMIMEHeaderKey(name)] {
+			return fmt.Errorf("header %s cannot be set directly", name)
+		}
+		if strings.ContainsAny(name, "\r\n: ") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header %q", name)
+		}
+	}
+	for _, attachment := range m.Attachments {
+		if attachment.Filename == "" || strings.ContainsAny(attachment.Filename, "\r\n\"") {
+			return fmt.Errorf("invalid attachment filename %q", attachment.Filename)
+		}
+	}
+	return nil
+}
+
+// Bytes encodes the message as MIME. Text and HTML bodies become a
+// multipart/alternative, wrapped in multipart/mixed when there are
+// attachments.
+func (m Message) Bytes() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", m.From)
+	if len(m.To) > 0 {
+		writeHeader(&buf, "To", strings.Join(m.To, ", "))
+	}
+	if len(m.Cc) > 0 {
+		writeHeader(&buf, "Cc", strings.Join(m.Cc, ", "))
+	}
+	if m.ReplyTo != "" {
+		writeHeader(&buf, "Reply-To", m.ReplyTo)
+	}
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	if !m.hasHeader("Date") {
+		writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	}
+	if !m.hasHeader("Message-Id") {
+		writeHeader(&buf, "Message-ID", messageID(m.From))
+	}
+	if m.Locale != "" {
+		writeHeader(&buf, "Content-Language", m.Locale)
+	}
+
+	names := make([]string, 0, len(m.Headers))
+	for name := range m.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHeader(&buf, name, m.Headers[name])
+	}
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	bodyHeader, body, err := m.body()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.Attachments) == 0 {
+		for _, name := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+			if value := bodyHeader.Get(name); value != "" {
+				writeHeader(&buf, name, value)
+			}
+		}
+		buf.WriteString("\r\n")
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	var content bytes.Buffer
+	mixed := multipart.NewWriter(&content)
+	writeHeader(&buf, "Content-Type", `multipart/mixed; boundary="`+mixed.Boundary()+`"`)
+	buf.WriteString("\r\n")
+
+	part, err := mixed.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(body); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range m.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Transfer-Encoding", "base64")
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+
+		part, err := mixed.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(encodeBase64(attachment.Data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write(content.Bytes())
+	return buf.Bytes(), nil
+}
+
+// body encodes the text and HTML bodies, returning the headers that
+// describe them
+func (m Message) body() (textproto.MIMEHeader, []byte, error) {
+	header := textproto.MIMEHeader{}
+
+	if m.Text == "" || m.HTML == "" {
+		contentType, content := "text/plain; charset=UTF-8", m.Text
+		if m.Text == "" {
+			contentType, content = "text/html; charset=UTF-8", m.HTML
+		}
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		encoded, err := encodeQuotedPrintable(content)
+		return header, encoded, err
+	}
+
+	var buf bytes.Buffer
+	alternative := multipart.NewWriter(&buf)
+	header.Set("Content-Type", `multipart/alternative; boundary="`+alternative.Boundary()+`"`)
+
+	// Plain text first, so clients that can show HTML prefer it
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	}
+	for _, part := range parts {
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set("Content-Type", part.contentType)
+		partHeader.Set("Content-Transfer-Encoding", "quoted-printable")
+
+		writer, err := alternative.CreatePart(partHeader)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded, err := encodeQuotedPrintable(part.content)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := writer.Write(encoded); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := alternative.Close(); err != nil {
+		return nil, nil, err
+	}
+	return header, buf.Bytes(), nil
+}
+
+func (m Message) hasHeader(canonicalName string) bool {
+	for name := range m.Headers {
+		if textproto.CanonicalMIMEHeaderKey(name) == canonicalName {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHeader(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name + ": " + value + "\r\n")
+}
+
+func encodeQuotedPrintable(content string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeBase64 wraps lines at 76 characters as MIME requires
+func encodeBase64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
+
+// messageID generates a unique Message-ID on the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndexByte(address.Address, '@'); i >= 0 {
+			domain = address.Address[i+1:]
+		}
+	}
+
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
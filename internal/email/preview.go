@@ -0,0 +1,65 @@
+package email
+
+import "time"
+
+// PreviewData returns sample data for rendering the named template in
+// development
+func PreviewData(name string) map[string]interface{} {
+	now := time.Now()
+
+	switch name {
+	case TemplateOTP:
+		return map[string]interface{}{"OTP": "123456", "ExpiresInMinutes": 5}
+	case TemplateVerifyEmail:
+		return map[string]interface{}{
+			"OTP":                "123456",
+			"ExpiresInMinutes":   5,
+			"VerifyLink":         "https://example.com/api/auth/verify-email?token=preview",
+			"LinkExpiresInHours": 24,
+		}
+	case TemplateEmailChange:
+		return map[string]interface{}{
+			"OTP":                "123456",
+			"ExpiresInMinutes":   5,
+			"ConfirmLink":        "https://example.com/api/auth/confirm-email-change?token=preview",
+			"LinkExpiresInHours": 24,
+		}
+	case TemplateEmailChangeNotice:
+		return map[string]interface{}{
+			"NewEmail":   "ada@example.org",
+			"RevokeLink": "https://example.com/api/auth/revoke-sessions?token=preview",
+		}
+	case TemplateReset:
+		return map[string]interface{}{"Code": "654321", "ExpiresInMinutes": 30}
+	case TemplateWelcome:
+		return map[string]interface{}{"Name": "Ada Lovelace"}
+	case TemplateNewDevice:
+		return map[string]interface{}{
+			"IPAddress":  "203.0.113.42",
+			"UserAgent":  "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Firefox/131.0",
+			"SignedInAt": now,
+			"RevokeLink": "https://example.com/api/auth/revoke-sessions?token=preview",
+		}
+	case TemplateSessionLimit:
+		return map[string]interface{}{
+			"MaxSessions": 3,
+			"IPAddress":   "203.0.113.42",
+			"UserAgent":   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Firefox/131.0",
+			"AttemptedAt": now,
+			"RevokeLink":  "https://example.com/api/auth/revoke-sessions?token=preview",
+		}
+	case TemplateAccountDeletion:
+		return map[string]interface{}{
+			"RestoreLink": "https://example.com/api/auth/restore-account?token=preview",
+			"PurgeAt":     now.Add(30 * 24 * time.Hour),
+		}
+	case TemplateInvitation:
+		return map[string]interface{}{
+			"OrgName":    "Acme Corp",
+			"InviteLink": "https://example.com/api/invitations/accept?token=preview",
+			"ExpiresAt":  now.Add(72 * time.Hour),
+		}
+	default:
+		return nil
+	}
+}
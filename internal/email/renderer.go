@@ -0,0 +1,360 @@
+// Package email renders the service's transactional emails from templates.
+//
+// Every email has an HTML and a plain text part. Each part wraps the named
+// template's "content" block in a shared layout, and may use the partials.
+// The text template also defines the "subject" block. Copy is looked up in
+// per-locale message catalogs through the t function, so the templates
+// themselves hold no language:
+//
+//	{{define "content"}}<p>{{t "otp.intro"}}</p>{{end}}
+//
+// Templates and catalogs are embedded, and a directory with the same
+// layout (templates/, templates/partials/, locales/) can override any file
+// or add locales.
+package email
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template names
+const (
+	TemplateOTP               = "otp"
+	TemplateVerifyEmail       = "verify-email"
+	TemplateEmailChange       = "email-change"
+	TemplateEmailChangeNotice = "email-change-notice"
+	TemplateReset             = "reset"
+	TemplateWelcome           = "welcome"
+	TemplateNewDevice         = "new-device"
+	TemplateSessionLimit      = "session-limit"
+	TemplateAccountDeletion   = "account-deletion"
+	TemplateInvitation        = "invitation"
+)
+
+// Templates lists every email the service sends
+var Templates = []string{
+	TemplateOTP,
+	TemplateVerifyEmail,
+	TemplateEmailChange,
+	TemplateEmailChangeNotice,
+	TemplateReset,
+	TemplateWelcome,
+	TemplateNewDevice,
+	TemplateSessionLimit,
+	TemplateAccountDeletion,
+	TemplateInvitation,
+}
+
+//go:embed templates locales
+var embedded embed.FS
+
+type templateSet struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// Renderer holds the parsed templates and message catalogs
+type Renderer struct {
+	templates     map[string]templateSet
+	catalogs      map[string]map[string]string
+	defaultLocale string
+}
+
+// NewRenderer loads the embedded templates, overridden by files in
+// overrideDir when it is set
+func NewRenderer(overrideDir, defaultLocale string) (*Renderer, error) {
+	files := overlayFS{base: embedded}
+	if overrideDir != "" {
+		if _, err := os.Stat(overrideDir); err != nil {
+			return nil, fmt.Errorf("email template directory: %w", err)
+		}
+		files.override = os.DirFS(overrideDir)
+	}
+
+	catalogs, err := loadCatalogs(files)
+	if err != nil {
+		return nil, err
+	}
+	defaultLocale = normalizeLocale(defaultLocale)
+	if _, ok := catalogs[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no message catalog for default locale %q", defaultLocale)
+	}
+
+	r := &Renderer{
+		templates:     map[string]templateSet{},
+		catalogs:      catalogs,
+		defaultLocale: defaultLocale,
+	}
+	for _, name := range Templates {
+		set, err := parseTemplateSet(files, name)
+		if err != nil {
+			return nil, err
+		}
+		r.templates[name] = set
+	}
+
+	return r, nil
+}
+
+// Locales returns the locales with a message catalog
+func (r *Renderer) Locales() []string {
+	locales := make([]string, 0, len(r.catalogs))
+	for locale := range r.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Render produces the named email in the closest available locale. A
+// regional locale such as "es-mx" falls back to "es", then to the default.
+func (r *Renderer) Render(name, locale string, data map[string]interface{}) (*Message, error) {
+	set, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	locale = r.resolveLocale(locale)
+	translate := r.translator(locale)
+
+	text, err := set.text.Clone()
+	if err != nil {
+		return nil, err
+	}
+	text.Funcs(texttemplate.FuncMap{"t": translate})
+
+	html, err := set.html.Clone()
+	if err != nil {
+		return nil, err
+	}
+	html.Funcs(htmltemplate.FuncMap{"t": translate})
+
+	message := &Message{Locale: locale}
+	var buf bytes.Buffer
+
+	if err := text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	message.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := text.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	message.Text = strings.TrimSpace(buf.String()) + "\n"
+
+	buf.Reset()
+	if err := html.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s HTML: %w", name, err)
+	}
+	message.HTML = buf.String()
+
+	return message, nil
+}
+
+// Translate looks a message up in the locale's catalog, for text sent
+// outside emails such as SMS
+func (r *Renderer) Translate(locale, key string, args ...interface{}) string {
+	return r.translator(r.resolveLocale(locale))(key, args...)
+}
+
+func (r *Renderer) resolveLocale(locale string) string {
+	locale = normalizeLocale(locale)
+	if _, ok := r.catalogs[locale]; ok {
+		return locale
+	}
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		if _, ok := r.catalogs[locale[:i]]; ok {
+			return locale[:i]
+		}
+	}
+	return r.defaultLocale
+}
+
+// translator looks messages up in the locale's catalog, falling back to the
+// default locale and finally to the key itself so gaps show in previews
+func (r *Renderer) translator(locale string) func(key string, args ...interface{}) string {
+	return func(key string, args ...interface{}) string {
+		message, ok := r.catalogs[locale][key]
+		if !ok {
+			if message, ok = r.catalogs[r.defaultLocale][key]; !ok {
+				return key
+			}
+		}
+		if len(args) == 0 {
+			return message
+		}
+		return fmt.Sprintf(message, args...)
+	}
+}
+
+// Link is a call to action rendered by the button partial
+type Link struct {
+	URL   string
+	Label string
+}
+
+// templateFuncs are available to every template. t is a placeholder that
+// lets templates parse before a locale is chosen.
+var templateFuncs = map[string]interface{}{
+	"t": func(key string, args ...interface{}) string { return key },
+	"link": func(url, label string) Link {
+		return Link{URL: url, Label: label}
+	},
+	// Dates are numeric so they read the same in every locale
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04 MST")
+	},
+}
+
+func parseTemplateSet(files fs.FS, name string) (templateSet, error) {
+	var set templateSet
+
+	htmlFiles, err := templateFiles(files, name, "html")
+	if err != nil {
+		return set, err
+	}
+	html := htmltemplate.New(name).Funcs(templateFuncs)
+	for _, file := range htmlFiles {
+		src, err := fs.ReadFile(files, file)
+		if err != nil {
+			return set, err
+		}
+		if _, err := html.New(file).Parse(string(src)); err != nil {
+			return set, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+
+	textFiles, err := templateFiles(files, name, "txt")
+	if err != nil {
+		return set, err
+	}
+	text := texttemplate.New(name).Funcs(templateFuncs)
+	for _, file := range textFiles {
+		src, err := fs.ReadFile(files, file)
+		if err != nil {
+			return set, err
+		}
+		if _, err := text.New(file).Parse(string(src)); err != nil {
+			return set, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+
+	for _, block := range []string{"layout", "content"} {
+		if html.Lookup(block) == nil || text.Lookup(block) == nil {
+			return set, fmt.Errorf("email template %s does not define %q", name, block)
+		}
+	}
+	if text.Lookup("subject") == nil {
+		return set, fmt.Errorf("email template %s does not define \"subject\"", name)
+	}
+
+	return templateSet{html: html, text: text}, nil
+}
+
+// templateFiles lists the layout, partials and content files for one part
+// of an email. The content file is parsed last so it can use the partials.
+func templateFiles(files fs.FS, name, format string) ([]string, error) {
+	partials, err := fs.Glob(files, "templates/partials/*."+format+".tmpl")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(partials)
+
+	list := append([]string{"templates/layout." + format + ".tmpl"}, partials...)
+	return append(list, "templates/"+name+"."+format+".tmpl"), nil
+}
+
+// loadCatalogs reads locales/<locale>.json files. Override catalogs are
+// merged key by key over the embedded ones.
+func loadCatalogs(files overlayFS) (map[string]map[string]string, error) {
+	catalogs := map[string]map[string]string{}
+
+	for _, layer := range []fs.FS{files.base, files.override} {
+		if layer == nil {
+			continue
+		}
+		paths, err := fs.Glob(layer, "locales/*.json")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range paths {
+			src, err := fs.ReadFile(layer, file)
+			if err != nil {
+				return nil, err
+			}
+			var messages map[string]string
+			if err := json.Unmarshal(src, &messages); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+			}
+
+			locale := normalizeLocale(strings.TrimSuffix(path.Base(file), ".json"))
+			if catalogs[locale] == nil {
+				catalogs[locale] = map[string]string{}
+			}
+			for key, message := range messages {
+				catalogs[locale][key] = message
+			}
+		}
+	}
+
+	return catalogs, nil
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// overlayFS serves files from override when present there, else from base.
+// Globs cover both.
+type overlayFS struct {
+	base     fs.FS
+	override fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if o.override != nil {
+		if file, err := o.override.Open(name); err == nil {
+			return file, nil
+		}
+	}
+	return o.base.Open(name)
+}
+
+func (o overlayFS) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(o.base, pattern)
+	if err != nil || o.override == nil {
+		return matches, err
+	}
+
+	extra, err := fs.Glob(o.override, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range extra {
+		if !containsPath(matches, match) {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+func containsPath(paths []string, target string) bool {
+	for _, p := range paths {
+		if p == target {
+			return true
+		}
+	}
+	return false
+}
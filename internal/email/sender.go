@@ -0,0 +1,101 @@
+package email
+
+import (
+	"auth-service/internal/registry"
+	"context"
+)
+
+// Sender delivers messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ProviderConfig holds the settings every provider draws from. Each
+// provider reads only the fields it needs.
+type ProviderConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	FilePath     string
+}
+
+// ProviderFactory builds a provider from configuration
+type ProviderFactory func(cfg ProviderConfig) (Sender, error)
+
+var providers = registry.New[ProviderConfig, Sender]("email")
+
+// Provider names
+const (
+	ProviderSMTP   = "smtp"
+	ProviderFile   = "file"
+	ProviderMemory = "memory"
+	ProviderLog    = "log"
+)
+
+func init() {
+	RegisterProvider(ProviderSMTP, newSMTPSender)
+	RegisterProvider(ProviderFile, newMboxSender)
+	RegisterProvider(ProviderMemory, func(ProviderConfig) (Sender, error) {
+		return NewMemorySender(), nil
+	})
+	RegisterProvider(ProviderLog, func(ProviderConfig) (Sender, error) {
+		return NewLogSender(), nil
+	})
+}
+
+// RegisterProvider makes a provider available by name, replacing any
+// provider registered under the same name
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers.Register(name, factory)
+}
+
+// Providers lists the registered provider names
+func Providers() []string {
+	return providers.Names()
+}
+
+// NewProvider builds the named provider
+func NewProvider(name string, cfg ProviderConfig) (Sender, error) {
+	return providers.New(name, cfg)
+}
+
+// Mailer is what the application sends email through. It fills in the
+// default sender and renders templated messages.
+type Mailer struct {
+	sender   Sender
+	renderer *Renderer
+	from     string
+}
+
+func NewMailer(sender Sender, renderer *Renderer, from string) *Mailer {
+	return &Mailer{
+		sender:   sender,
+		renderer: renderer,
+		from:     from,
+	}
+}
+
+// Send delivers the message, from the default address unless it sets its own
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	return m.sender.Send(ctx, msg)
+}
+
+// SendTemplate renders the named template in the recipient's locale and
+// sends it to them
+func (m *Mailer) SendTemplate(ctx context.Context, to, locale, name string, data map[string]interface{}) error {
+	msg, err := m.renderer.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+
+	msg.To = []string{to}
+	return m.Send(ctx, *msg)
+}
@@ -0,0 +1,51 @@
+// Package emailaddress reduces email addresses to a canonical form and
+// decides which domains may be used to sign up.
+package emailaddress
+
+import "strings"
+
+// Rules control how addresses are reduced to their canonical form. Case is
+// always folded; Gmail ignores dots and anything after a plus in the local
+// part, so those can be folded too.
+type Rules struct {
+	GmailDots bool
+	GmailPlus bool
+}
+
+// Normalize returns the canonical form of an address, used to tell whether
+// two addresses reach the same mailbox. Mail is still sent to the address
+// as entered.
+func (r Rules) Normalize(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return address
+	}
+	local, host := address[:at], strings.TrimSuffix(address[at+1:], ".")
+
+	if host == "googlemail.com" {
+		host = "gmail.com"
+	}
+	if host == "gmail.com" {
+		if r.GmailPlus {
+			local, _, _ = strings.Cut(local, "+")
+		}
+		if r.GmailDots {
+			local = strings.ReplaceAll(local, ".", "")
+		}
+	}
+
+	return local + "@" + host
+}
+
+// Domain returns the lowercased domain of an address, or an empty string
+// when it has none
+func Domain(address string) string {
+	address = strings.TrimSpace(address)
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(address[at+1:]), ".")
+}
@@ -0,0 +1,144 @@
+package emailaddress
+
+import (
+	"auth-service/internal/domain"
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// disposableDomains is the bundled list of throwaway mail providers, one
+// domain per line
+//
+//go:embed disposable_domains.txt
+var disposableDomains string
+
+// PolicyConfig lists the domains that may and may not register. Entries
+// also cover their subdomains.
+type PolicyConfig struct {
+	Rules Rules
+	// AllowedDomains, when not empty, are the only domains accepted
+	AllowedDomains []string
+	BlockedDomains []string
+	// BlockDisposable rejects the bundled disposable domains, plus any
+	// listed in the file at DisposableListPath
+	BlockDisposable    bool
+	DisposableListPath string
+}
+
+// Policy normalizes addresses and decides which domains may be used for an
+// account
+type Policy struct {
+	rules      Rules
+	allowed    map[string]bool
+	blocked    map[string]bool
+	disposable map[string]bool
+}
+
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	policy := &Policy{
+		rules:   cfg.Rules,
+		allowed: domainSet(cfg.AllowedDomains),
+		blocked: domainSet(cfg.BlockedDomains),
+	}
+
+	if cfg.BlockDisposable {
+		policy.disposable = map[string]bool{}
+		if err := readDomains(strings.NewReader(disposableDomains), policy.disposable); err != nil {
+			return nil, fmt.Errorf("failed to read bundled disposable domains: %w", err)
+		}
+
+		if cfg.DisposableListPath != "" {
+			file, err := os.Open(cfg.DisposableListPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open disposable domain list: %w", err)
+			}
+			defer file.Close()
+
+			if err := readDomains(file, policy.disposable); err != nil {
+				return nil, fmt.Errorf("failed to read disposable domain list: %w", err)
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+// Normalize returns the canonical form of the address under the policy's rules
+func (p *Policy) Normalize(address string) string {
+	return p.rules.Normalize(address)
+}
+
+// Check reports whether the address's domain may be used for an account.
+// Blocked domains always lose; an explicitly allowed domain is accepted
+// even when it is on the disposable list.
+func (p *Policy) Check(address string) error {
+	host := Domain(address)
+
+	if matches(p.blocked, host) {
+		return domain.ErrEmailDomainBlocked
+	}
+	if len(p.allowed) > 0 {
+		if !matches(p.allowed, host) {
+			return domain.ErrEmailDomainBlocked
+		}
+		return nil
+	}
+	if matches(p.disposable, host) {
+		return domain.ErrDisposableEmail
+	}
+
+	return nil
+}
+
+// matches reports whether the host or any domain above it is in the set
+func matches(set map[string]bool, host string) bool {
+	if len(set) == 0 || host == "" {
+		return false
+	}
+	for {
+		if set[host] {
+			return true
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot < 0 {
+			return false
+		}
+		host = host[dot+1:]
+	}
+}
+
+func domainSet(domains []string) map[string]bool {
+	set := make(map[string]bool, len(domains))
+	for _, name := range domains {
+		if name = normalizeDomain(name); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// readDomains adds each domain in the list to the set, skipping blank lines
+// and # comments
+func readDomains(r io.Reader, set map[string]bool) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		if name := normalizeDomain(text); name != "" {
+			set[name] = true
+		}
+	}
+	return scanner.Err()
+}
+
+func normalizeDomain(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "@")
+	return strings.Trim(name, ".")
+}
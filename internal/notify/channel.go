@@ -0,0 +1,84 @@
+// Package notify delivers short messages, such as one-time codes, to phone
+// numbers over SMS, WhatsApp or voice calls. Providers are registered by
+// name like email providers, so deployments pick one through configuration.
+package notify
+
+import (
+	"auth-service/internal/registry"
+	"context"
+)
+
+// Channels a notification can be delivered over
+const (
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+	ChannelVoice    = "voice"
+)
+
+// Channels lists every channel, in order of preference
+var Channels = []string{ChannelSMS, ChannelWhatsApp, ChannelVoice}
+
+// IsChannel reports whether name is a known channel
+func IsChannel(name string) bool {
+	for _, channel := range Channels {
+		if channel == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is a message for one phone number. Code is set for one-time
+// codes so voice providers can read it out digit by digit.
+type Notification struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Body    string `json:"body"`
+	Code    string `json:"code,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+}
+
+// NotificationChannel delivers notifications to phone numbers
+type NotificationChannel interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+// ProviderConfig holds the settings of every notification provider
+type ProviderConfig struct {
+	From          string
+	WebhookURL    string
+	WebhookSecret string
+	TwilioSID     string
+	TwilioToken   string
+}
+
+type ProviderFactory func(cfg ProviderConfig) (NotificationChannel, error)
+
+var providers = registry.New[ProviderConfig, NotificationChannel]("notification")
+
+// Provider names
+const (
+	ProviderWebhook = "webhook"
+	ProviderConsole = "console"
+	ProviderTwilio  = "twilio"
+)
+
+func init() {
+	RegisterProvider(ProviderWebhook, newWebhookChannel)
+	RegisterProvider(ProviderConsole, func(ProviderConfig) (NotificationChannel, error) {
+		return NewConsoleChannel(), nil
+	})
+	RegisterProvider(ProviderTwilio, newTwilioChannel)
+}
+
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers.Register(name, factory)
+}
+
+func Providers() []string {
+	return providers.Names()
+}
+
+func NewProvider(name string, cfg ProviderConfig) (NotificationChannel, error) {
+	return providers.New(name, cfg)
+}
@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"os"
+)
+
+// ConsoleChannel prints notifications to stdout instead of sending them,
+// for local development
+type ConsoleChannel struct {
+	logger *log.Logger
+}
+
+func NewConsoleChannel() *ConsoleChannel {
+	return &ConsoleChannel{
+		logger: log.New(os.Stdout, "[DEV NOTIFY] ", log.LstdFlags),
+	}
+}
+
+func (c *ConsoleChannel) Send(_ context.Context, n Notification) error {
+	c.logger.Printf("\n==================================")
+	c.logger.Printf("📱 New %s message", n.Channel)
+	c.logger.Printf("☎️  To: %s", n.To)
+	c.logger.Printf("\n%s", n.Body)
+	c.logger.Printf("==================================\n")
+	return nil
+}
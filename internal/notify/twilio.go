@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	twilioAPIBase = "https://api.twilio.com/2010-04-01/Accounts/"
+	twilioTimeout = 10 * time.Second
+)
+
+// twilioChannel sends SMS and WhatsApp messages through Twilio's Messages
+// API and places voice calls that read the message out through its Calls API
+type twilioChannel struct {
+	sid    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+func newTwilioChannel(cfg ProviderConfig) (NotificationChannel, error) {
+	if cfg.TwilioSID == "" || cfg.TwilioToken == "" || cfg.From == "" {
+		return nil, errors.New("twilio notification provider requires an account SID, auth token and sender number")
+	}
+
+	return &twilioChannel{
+		sid:    cfg.TwilioSID,
+		token:  cfg.TwilioToken,
+		from:   cfg.From,
+		client: &http.Client{Timeout: twilioTimeout},
+	}, nil
+}
+
+func (t *twilioChannel) Send(ctx context.Context, n Notification) error {
+	form := url.Values{}
+	resource := "Messages.json"
+
+	switch n.Channel {
+	case ChannelSMS:
+		form.Set("To", n.To)
+		form.Set("From", t.from)
+		form.Set("Body", n.Body)
+	case ChannelWhatsApp:
+		form.Set("To", "whatsapp:"+n.To)
+		form.Set("From", "whatsapp:"+t.from)
+		form.Set("Body", n.Body)
+	case ChannelVoice:
+		resource = "Calls.json"
+		form.Set("To", n.To)
+		form.Set("From", t.from)
+		form.Set("Twiml", voiceTwiML(n))
+	default:
+		return fmt.Errorf("unsupported notification channel %q", n.Channel)
+	}
+
+	endpoint := twilioAPIBase + url.PathEscape(t.sid) + "/" + resource
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.sid, t.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio returned %s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("twilio returned %s", resp.Status)
+	}
+	return nil
+}
+
+// voiceTwiML reads the message out twice, with any code spelled digit by
+// digit so it is not pronounced as one large number
+func voiceTwiML(n Notification) string {
+	text := n.Body
+	if n.Code != "" {
+		text = strings.ReplaceAll(text, n.Code, strings.Join(strings.Split(n.Code, ""), ", "))
+	}
+
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(text))
+
+	say := "<Say"
+	if n.Locale != "" {
+		var language strings.Builder
+		_ = xml.EscapeText(&language, []byte(n.Locale))
+		say += ` language="` + language.String() + `"`
+	}
+	say += ">" + escaped.String() + "</Say>"
+
+	return "<Response>" + say + `<Pause length="1"/>` + say + "</Response>"
+}
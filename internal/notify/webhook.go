@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body,
+// keyed with the webhook secret, when one is configured
+const WebhookSignatureHeader = "X-Notification-Signature"
+
+// webhookChannel posts each notification as JSON to a URL, leaving delivery
+// to whatever listens there. Any 2xx response counts as accepted.
+type webhookChannel struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func newWebhookChannel(cfg ProviderConfig) (NotificationChannel, error) {
+	if cfg.WebhookURL == "" {
+		return nil, errors.New("webhook notification provider requires a URL")
+	}
+
+	return &webhookChannel{
+		url:    cfg.WebhookURL,
+		secret: cfg.WebhookSecret,
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+func (w *webhookChannel) Send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notification webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+	return nil
+}
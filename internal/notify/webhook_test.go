@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type webhookRequest struct {
+	method      string
+	contentType string
+	signature   string
+	body        []byte
+}
+
+// newWebhookServer records each request and answers with the given status
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, *[]webhookRequest) {
+	t.Helper()
+
+	var requests []webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %v", err)
+		}
+		requests = append(requests, webhookRequest{
+			method:      r.Method,
+			contentType: r.Header.Get("Content-Type"),
+			signature:   r.Header.Get(WebhookSignatureHeader),
+			body:        body,
+		})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestWebhookChannelSend(t *testing.T) {
+	notification := Notification{
+		Channel: ChannelSMS,
+		To:      "+14155550100",
+		Body:    "Your code is 123456",
+		Code:    "123456",
+		Locale:  "en",
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		status  int
+		wantErr string
+	}{
+		{"signed", "webhook-secret", http.StatusOK, ""},
+		{"unsigned without a secret", "", http.StatusNoContent, ""},
+		{"client error", "webhook-secret", http.StatusBadRequest, "400 Bad Request"},
+		{"server error", "webhook-secret", http.StatusBadGateway, "502 Bad Gateway"},
+		{"3xx is not accepted", "", http.StatusNotModified, "304 Not Modified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newWebhookServer(t, tt.status)
+			channel, err := NewProvider(ProviderWebhook, ProviderConfig{WebhookURL: server.URL, WebhookSecret: tt.secret})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = channel.Send(context.Background(), notification)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("got error %v, want it to contain %q", err, tt.wantErr)
+			}
+
+			if len(*requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(*requests))
+			}
+			req := (*requests)[0]
+			if req.method != http.MethodPost || req.contentType != "application/json" {
+				t.Fatalf("got %s with Content-Type %q, want a JSON POST", req.method, req.contentType)
+			}
+
+			var got Notification
+			if err := json.Unmarshal(req.body, &got); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+			if got != notification {
+				t.Fatalf("got body %+v, want %+v", got, notification)
+			}
+
+			wantSignature := ""
+			if tt.secret != "" {
+				mac := hmac.New(sha256.New, []byte(tt.secret))
+				mac.Write(req.body)
+				wantSignature = hex.EncodeToString(mac.Sum(nil))
+			}
+			if req.signature != wantSignature {
+				t.Fatalf("got signature %q, want %q", req.signature, wantSignature)
+			}
+		})
+	}
+}
+
+func TestWebhookChannelUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	channel, err := NewProvider(ProviderWebhook, ProviderConfig{WebhookURL: url})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := channel.Send(context.Background(), Notification{Channel: ChannelSMS, To: "+14155550100"}); err == nil {
+		t.Fatalf("expected an error for an unreachable webhook")
+	}
+}
+
+func TestNewProviderRejectsUnknownAndMisconfigured(t *testing.T) {
+	if _, err := NewProvider("carrier-pigeon", ProviderConfig{}); err == nil || !strings.Contains(err.Error(), `unknown notification provider "carrier-pigeon"`) {
+		t.Fatalf("got error %v for an unknown provider", err)
+	}
+	if _, err := NewProvider(ProviderWebhook, ProviderConfig{}); err == nil {
+		t.Fatalf("expected an error for a webhook without a URL")
+	}
+}
@@ -0,0 +1,145 @@
+// Package policy evaluates attribute-based authorization rules loaded from
+// policy files.
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Rule grants or forbids the listed actions when its condition holds.
+// Action patterns may use '*' wildcards, as in "documents:*" or "*:read".
+type Rule struct {
+	Name      string
+	Effect    Effect
+	Actions   []string
+	Condition expr
+	Source    string
+}
+
+// Input is the request being authorized. Subject and Resource are plain
+// attribute maps so policies can reference any field by name.
+type Input struct {
+	Subject  map[string]interface{}
+	Action   string
+	Resource map[string]interface{}
+}
+
+// Result names the rule that decided the request, if any
+type Result struct {
+	Allowed bool
+	Rule    string
+}
+
+// Engine holds an immutable, parsed rule set
+type Engine struct {
+	rules   []*Rule
+	version string
+}
+
+// LoadDir parses every *.policy file in dir, in lexical order
+func LoadDir(dir string) (*Engine, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.policy"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	sources := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file: %w", err)
+		}
+		sources[filepath.Base(path)] = string(data)
+	}
+
+	return New(sources)
+}
+
+// New builds an engine from policy sources keyed by file name
+func New(sources map[string]string) (*Engine, error) {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	engine := &Engine{}
+	seen := map[string]string{}
+
+	for _, name := range names {
+		rules, err := parse(sources[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, rule := range rules {
+			if other, ok := seen[rule.Name]; ok {
+				return nil, fmt.Errorf("%s: rule %q is already defined in %s", name, rule.Name, other)
+			}
+			seen[rule.Name] = name
+			rule.Source = name
+		}
+
+		engine.rules = append(engine.rules, rules...)
+		fmt.Fprintf(hash, "%s\x00%s\x00", name, sources[name])
+	}
+
+	engine.version = hex.EncodeToString(hash.Sum(nil))[:16]
+	return engine, nil
+}
+
+// Version identifies the loaded rule set, so cached decisions can be keyed
+// to the policies that produced them
+func (e *Engine) Version() string {
+	return e.version
+}
+
+// Evaluate applies deny-overrides: any matching deny wins, otherwise the
+// first matching allow grants access, and no match denies by default.
+func (e *Engine) Evaluate(input Input) Result {
+	var allowed *Rule
+	for _, rule := range e.rules {
+		if !rule.matchesAction(input.Action) {
+			continue
+		}
+		if rule.Condition != nil && !truthy(rule.Condition.eval(input)) {
+			continue
+		}
+
+		if rule.Effect == EffectDeny {
+			return Result{Allowed: false, Rule: rule.Name}
+		}
+		if allowed == nil {
+			allowed = rule
+		}
+	}
+
+	if allowed == nil {
+		return Result{Allowed: false}
+	}
+	return Result{Allowed: true, Rule: allowed.Name}
+}
+
+func (r *Rule) matchesAction(action string) bool {
+	for _, pattern := range r.Actions {
+		if matched, _ := path.Match(pattern, action); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	engine, err := New(map[string]string{
+		"a.policy": `
+rule "owner" {
+    allow ["documents:*"]
+    when resource.owner_id != null and resource.owner_id == subject.id
+}
+
+rule "reader" {
+    allow ["*:read"]
+    when resource.org_id == subject.org_id
+}
+`,
+		"b.policy": `
+rule "locked" {
+    deny ["documents:write", "documents:delete"]
+    when resource.locked == true
+}
+
+rule "minimum-level" {
+    allow ["reports:read"]
+    when subject.level >= 3
+}
+
+rule "support" {
+    allow ["tickets:*"]
+    when "support" in subject.roles and not (resource.priority in ["critical", "security"])
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		input       Input
+		wantAllowed bool
+		wantRule    string
+	}{
+		{
+			name: "owner allowed",
+			input: Input{
+				Subject:  map[string]interface{}{"id": "u1"},
+				Action:   "documents:write",
+				Resource: map[string]interface{}{"owner_id": "u1"},
+			},
+			wantAllowed: true,
+			wantRule:    "owner",
+		},
+		{
+			name: "deny overrides allow",
+			input: Input{
+				Subject:  map[string]interface{}{"id": "u1"},
+				Action:   "documents:write",
+				Resource: map[string]interface{}{"owner_id": "u1", "locked": true},
+			},
+			wantAllowed: false,
+			wantRule:    "locked",
+		},
+		{
+			name: "deny outside its actions does not apply",
+			input: Input{
+				Subject:  map[string]interface{}{"id": "u1"},
+				Action:   "documents:read",
+				Resource: map[string]interface{}{"owner_id": "u1", "locked": true},
+			},
+			wantAllowed: true,
+			wantRule:    "owner",
+		},
+		{
+			name: "first matching allow wins",
+			input: Input{
+				Subject:  map[string]interface{}{"id": "u1", "org_id": "o1"},
+				Action:   "documents:read",
+				Resource: map[string]interface{}{"owner_id": "u1", "org_id": "o1"},
+			},
+			wantAllowed: true,
+			wantRule:    "owner",
+		},
+		{
+			name: "wildcard action",
+			input: Input{
+				Subject:  map[string]interface{}{"id": "u2", "org_id": "o1"},
+				Action:   "invoices:read",
+				Resource: map[string]interface{}{"org_id": "o1"},
+			},
+			wantAllowed: true,
+			wantRule:    "reader",
+		},
+		{
+			name: "no matching rule denies",
+			input: Input{
+				Subject:  map[string]interface{}{"id": "u2", "org_id": "o1"},
+				Action:   "invoices:write",
+				Resource: map[string]interface{}{"org_id": "o1"},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "null guard skips missing owner",
+			input: Input{
+				Subject:  map[string]interface{}{},
+				Action:   "documents:write",
+				Resource: map[string]interface{}{},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "numbers compare across Go types",
+			input: Input{
+				Subject: map[string]interface{}{"level": 3, "org_id": "o1"},
+				Action:  "reports:read",
+			},
+			wantAllowed: true,
+			wantRule:    "minimum-level",
+		},
+		{
+			name: "mismatched types compare as false",
+			input: Input{
+				Subject: map[string]interface{}{"level": "9", "org_id": "o1"},
+				Action:  "reports:read",
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "in over a string slice",
+			input: Input{
+				Subject:  map[string]interface{}{"roles": []string{"user", "support"}},
+				Action:   "tickets:close",
+				Resource: map[string]interface{}{"priority": "low"},
+			},
+			wantAllowed: true,
+			wantRule:    "support",
+		},
+		{
+			name: "not excludes listed values",
+			input: Input{
+				Subject:  map[string]interface{}{"roles": []string{"support"}},
+				Action:   "tickets:close",
+				Resource: map[string]interface{}{"priority": "security"},
+			},
+			wantAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.Evaluate(tt.input)
+			if result.Allowed != tt.wantAllowed || result.Rule != tt.wantRule {
+				t.Fatalf("got allowed=%v rule=%q, want allowed=%v rule=%q", result.Allowed, result.Rule, tt.wantAllowed, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]string
+		wantErr string
+	}{
+		{
+			name:    "parse error names the file",
+			sources: map[string]string{"broken.policy": `rule "r" { allow ["*"] when }`},
+			wantErr: "broken.policy: line 1:",
+		},
+		{
+			name: "duplicate rule names",
+			sources: map[string]string{
+				"a.policy": `rule "r" { allow ["*"] }`,
+				"b.policy": `rule "r" { deny ["*"] }`,
+			},
+			wantErr: `b.policy: rule "r" is already defined in a.policy`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.sources)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVersionTracksSources(t *testing.T) {
+	sources := map[string]string{"a.policy": `rule "r" { allow ["*"] }`}
+
+	first, err := New(sources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := New(sources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changed, err := New(map[string]string{"a.policy": `rule "r" { deny ["*"] }`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first.Version() != second.Version() {
+		t.Fatalf("identical sources got versions %s and %s", first.Version(), second.Version())
+	}
+	if first.Version() == changed.Version() {
+		t.Fatalf("changed sources kept version %s", first.Version())
+	}
+}
+
+func TestLoadDirParsesShippedPolicies(t *testing.T) {
+	engine, err := LoadDir("../../policies")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := engine.Evaluate(Input{
+		Subject:  map[string]interface{}{"id": "u1", "org_id": "o1", "roles": []string{"user"}},
+		Action:   "documents:read",
+		Resource: map[string]interface{}{"org_id": "o2"},
+	})
+	if result.Allowed || result.Rule != "cross-tenant" {
+		t.Fatalf("got allowed=%v rule=%q, want a cross-tenant deny", result.Allowed, result.Rule)
+	}
+}
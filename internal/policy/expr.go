@@ -0,0 +1,169 @@
+package policy
+
+// expr is a node of a parsed when clause. Evaluation never fails: missing
+// attributes are null and mismatched types simply compare as false.
+type expr interface {
+	eval(input Input) interface{}
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(Input) interface{} {
+	return l.value
+}
+
+type attrPath []string
+
+func (a attrPath) eval(input Input) interface{} {
+	var current interface{}
+	switch a[0] {
+	case "action":
+		return input.Action
+	case "subject":
+		current = input.Subject
+	case "resource":
+		current = input.Resource
+	}
+
+	for _, field := range a[1:] {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[field]
+	}
+
+	return normalize(current)
+}
+
+type notExpr struct {
+	operand expr
+}
+
+func (n notExpr) eval(input Input) interface{} {
+	return !truthy(n.operand.eval(input))
+}
+
+type andExpr struct {
+	left, right expr
+}
+
+func (a andExpr) eval(input Input) interface{} {
+	return truthy(a.left.eval(input)) && truthy(a.right.eval(input))
+}
+
+type orExpr struct {
+	left, right expr
+}
+
+func (o orExpr) eval(input Input) interface{} {
+	return truthy(o.left.eval(input)) || truthy(o.right.eval(input))
+}
+
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+func (c compareExpr) eval(input Input) interface{} {
+	left := c.left.eval(input)
+	right := c.right.eval(input)
+
+	switch c.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if equal(left, item) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			return compareOrdered(c.op, l < r, l == r)
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return compareOrdered(c.op, l < r, l == r)
+		}
+	}
+	return false
+}
+
+func compareOrdered(op string, less, same bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || same
+	case ">":
+		return !less && !same
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+func truthy(value interface{}) bool {
+	b, ok := value.(bool)
+	return ok && b
+}
+
+func equal(a, b interface{}) bool {
+	a, b = normalize(a), normalize(b)
+
+	listA, okA := a.([]interface{})
+	listB, okB := b.([]interface{})
+	if okA || okB {
+		if !okA || !okB || len(listA) != len(listB) {
+			return false
+		}
+		for i := range listA {
+			if !equal(listA[i], listB[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if _, ok := a.(map[string]interface{}); ok {
+		return false
+	}
+	if _, ok := b.(map[string]interface{}); ok {
+		return false
+	}
+
+	return a == b
+}
+
+// normalize maps Go values onto the JSON-like types the evaluator compares
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = item
+		}
+		return list
+	default:
+		return value
+	}
+}
@@ -0,0 +1,301 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+)
+
+// Policy files hold one or more rules:
+//
+//	# Owners may do anything with their own documents
+//	rule "document-owner" {
+//	    allow ["documents:*"]
+//	    when resource.owner_id == subject.id
+//	}
+//
+// The effect is allow or deny, followed by the action patterns it covers.
+// The optional when clause is an expression over subject, resource and
+// action using ==, !=, <, <=, >, >=, in, and, or, not and parentheses.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(src string) ([]*Rule, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	var rules []*Rule
+	for p.peek().kind != tokenEOF {
+		rule, err := p.parseRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) is(kind tokenKind, value string) bool {
+	tok := p.peek()
+	return tok.kind == kind && tok.value == value
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.is(kind, value) {
+		return p.errorf("expected %q, found %s", value, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseRule() (*Rule, error) {
+	if err := p.expect(tokenIdent, "rule"); err != nil {
+		return nil, err
+	}
+
+	name := p.next()
+	if name.kind != tokenString || name.value == "" {
+		return nil, p.errorf("rule name must be a non-empty string")
+	}
+	rule := &Rule{Name: name.value}
+
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+
+	effect := p.next()
+	switch {
+	case effect.kind == tokenIdent && effect.value == string(EffectAllow):
+		rule.Effect = EffectAllow
+	case effect.kind == tokenIdent && effect.value == string(EffectDeny):
+		rule.Effect = EffectDeny
+	default:
+		return nil, fmt.Errorf("line %d: expected allow or deny, found %s", effect.line, effect)
+	}
+
+	actions, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		pattern, ok := action.(string)
+		if !ok || pattern == "" {
+			return nil, p.errorf("actions must be non-empty strings")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, p.errorf("invalid action pattern %q", pattern)
+		}
+		rule.Actions = append(rule.Actions, pattern)
+	}
+	if len(rule.Actions) == 0 {
+		return nil, p.errorf("rule %q lists no actions", rule.Name)
+	}
+
+	if p.is(tokenIdent, "when") {
+		p.next()
+		if rule.Condition, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expect(tokenPunct, "}"); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(tokenIdent, "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is(tokenIdent, "and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.is(tokenIdent, "not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	var op string
+	switch tok := p.peek(); {
+	case tok.kind == tokenOperator:
+		op = tok.value
+	case tok.kind == tokenIdent && tok.value == "in":
+		op = "in"
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return compareExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (expr, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenString:
+		p.next()
+		return literal{tok.value}, nil
+	case tokenNumber:
+		p.next()
+		value, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid number %s", tok.line, tok)
+		}
+		return literal{value}, nil
+	case tokenPunct:
+		switch tok.value {
+		case "(":
+			p.next()
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenPunct, ")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			values, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			return literal{values}, nil
+		}
+	case tokenIdent:
+		switch tok.value {
+		case "true", "false":
+			p.next()
+			return literal{tok.value == "true"}, nil
+		case "null":
+			p.next()
+			return literal{nil}, nil
+		case "subject", "resource", "action":
+			return p.parsePath()
+		}
+	}
+
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+// parsePath reads subject.x.y, resource.x.y or action
+func (p *parser) parsePath() (expr, error) {
+	attr := attrPath{p.next().value}
+
+	for p.is(tokenPunct, ".") {
+		p.next()
+		field := p.next()
+		if field.kind != tokenIdent {
+			return nil, fmt.Errorf("line %d: expected attribute name, found %s", field.line, field)
+		}
+		attr = append(attr, field.value)
+	}
+
+	if attr[0] == "action" && len(attr) > 1 {
+		return nil, p.errorf("action has no attributes")
+	}
+
+	return attr, nil
+}
+
+// parseList reads a bracketed list of literals
+func (p *parser) parseList() ([]interface{}, error) {
+	if err := p.expect(tokenPunct, "["); err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{}
+	for !p.is(tokenPunct, "]") {
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := item.(literal)
+		if !ok {
+			return nil, p.errorf("lists may only contain literals")
+		}
+		values = append(values, lit.value)
+
+		if !p.is(tokenPunct, ",") {
+			break
+		}
+		p.next()
+	}
+
+	if err := p.expect(tokenPunct, "]"); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,156 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	src := `
+# Comments and blank lines are ignored
+rule "owner" {
+    allow ["documents:*", "*:read"]
+    when resource.owner_id == subject.id
+}
+
+rule "banned" {
+    deny ["*"]
+}
+`
+	rules, err := parse(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+
+	owner := rules[0]
+	if owner.Name != "owner" || owner.Effect != EffectAllow {
+		t.Fatalf("got rule %q with effect %q", owner.Name, owner.Effect)
+	}
+	if !reflect.DeepEqual(owner.Actions, []string{"documents:*", "*:read"}) {
+		t.Fatalf("got actions %v", owner.Actions)
+	}
+	want := compareExpr{op: "==", left: attrPath{"resource", "owner_id"}, right: attrPath{"subject", "id"}}
+	if !reflect.DeepEqual(owner.Condition, want) {
+		t.Fatalf("got condition %#v, want %#v", owner.Condition, want)
+	}
+
+	banned := rules[1]
+	if banned.Name != "banned" || banned.Effect != EffectDeny || banned.Condition != nil {
+		t.Fatalf("got rule %q with effect %q and condition %#v", banned.Name, banned.Effect, banned.Condition)
+	}
+}
+
+func TestParseOperands(t *testing.T) {
+	tests := []struct {
+		when string
+		want expr
+	}{
+		{`action == "documents:read"`, compareExpr{op: "==", left: attrPath{"action"}, right: literal{"documents:read"}}},
+		{`subject.level >= -2.5`, compareExpr{op: ">=", left: attrPath{"subject", "level"}, right: literal{-2.5}}},
+		{`resource.a.b != null`, compareExpr{op: "!=", left: attrPath{"resource", "a", "b"}, right: literal{nil}}},
+		{`subject.verified == true`, compareExpr{op: "==", left: attrPath{"subject", "verified"}, right: literal{true}}},
+		{`"admin" in subject.roles`, compareExpr{op: "in", left: literal{"admin"}, right: attrPath{"subject", "roles"}}},
+		{`subject.role in ["owner", 1, false]`, compareExpr{op: "in", left: attrPath{"subject", "role"}, right: literal{[]interface{}{"owner", 1.0, false}}}},
+		{`"say \"hi\"" == resource.greeting`, compareExpr{op: "==", left: literal{`say "hi"`}, right: attrPath{"resource", "greeting"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			rules, err := parse(`rule "r" { allow ["*"] when ` + tt.when + ` }`)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rules[0].Condition; !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	a := attrPath{"subject", "a"}
+	b := attrPath{"subject", "b"}
+	c := attrPath{"subject", "c"}
+
+	tests := []struct {
+		when string
+		want expr
+	}{
+		{"subject.a or subject.b and subject.c", orExpr{a, andExpr{b, c}}},
+		{"subject.a and subject.b or subject.c", orExpr{andExpr{a, b}, c}},
+		{"(subject.a or subject.b) and subject.c", andExpr{orExpr{a, b}, c}},
+		{"not subject.a and subject.b", andExpr{notExpr{a}, b}},
+		{"not (subject.a and subject.b)", notExpr{andExpr{a, b}}},
+		{"not not subject.a", notExpr{notExpr{a}}},
+		{"subject.a or subject.b or subject.c", orExpr{orExpr{a, b}, c}},
+		{"subject.a and subject.b and subject.c", andExpr{andExpr{a, b}, c}},
+		{
+			"subject.a == 1 and subject.b in [2] or not subject.c",
+			orExpr{
+				andExpr{
+					compareExpr{op: "==", left: a, right: literal{1.0}},
+					compareExpr{op: "in", left: b, right: literal{[]interface{}{2.0}}},
+				},
+				notExpr{c},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			rules, err := parse(`rule "r" { allow ["*"] when ` + tt.when + ` }`)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rules[0].Condition; !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"missing rule keyword", `"r" { allow ["*"] }`, `expected "rule"`},
+		{"empty name", `rule "" { allow ["*"] }`, "rule name must be a non-empty string"},
+		{"unquoted name", `rule r { allow ["*"] }`, "rule name must be a non-empty string"},
+		{"unknown effect", `rule "r" { permit ["*"] }`, "expected allow or deny"},
+		{"no actions", `rule "r" { allow [] }`, "lists no actions"},
+		{"non-string action", `rule "r" { allow [1] }`, "actions must be non-empty strings"},
+		{"invalid action pattern", `rule "r" { allow ["[a"] }`, "invalid action pattern"},
+		{"unclosed rule", `rule "r" { allow ["*"]`, `expected "}"`},
+		{"unterminated string", `rule "r" { allow ["*] }`, "unterminated string"},
+		{"unexpected character", `rule "r" { allow ["*"] when subject.a & subject.b }`, "unexpected '&'"},
+		{"single equals", `rule "r" { allow ["*"] when subject.a = 1 }`, "unexpected '='"},
+		{"dangling operator", `rule "r" { allow ["*"] when subject.a == }`, `unexpected "}"`},
+		{"dangling and", `rule "r" { allow ["*"] when subject.a and }`, `unexpected "}"`},
+		{"unbalanced parenthesis", `rule "r" { allow ["*"] when (subject.a }`, `expected ")"`},
+		{"chained comparison", `rule "r" { allow ["*"] when subject.a == 1 == 2 }`, `expected "}"`},
+		{"unknown root", `rule "r" { allow ["*"] when user.id == 1 }`, `unexpected "user"`},
+		{"action attribute", `rule "r" { allow ["*"] when action.name == "x" }`, "action has no attributes"},
+		{"missing attribute name", `rule "r" { allow ["*"] when subject. == 1 }`, "expected attribute name"},
+		{"attribute in list", `rule "r" { allow ["*"] when subject.a in [subject.b] }`, "lists may only contain literals"},
+		{"invalid number", `rule "r" { allow ["*"] when subject.a == 1.2.3 }`, "invalid number"},
+		{"error line", "rule \"r\" {\n    allow [\"*\"]\n    when subject.a ==\n}", "line 4:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.src)
+			if err == nil {
+				t.Fatalf("expected an error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package registry maps provider names to factories, so deployments pick an
+// implementation, such as an email or phone notification provider, through
+// configuration.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds the factories for one kind of provider. C is the
+// configuration every factory draws from and T what they build.
+type Registry[C, T any] struct {
+	kind      string
+	mu        sync.RWMutex
+	factories map[string]func(C) (T, error)
+}
+
+// New returns an empty registry. kind names the providers in errors, e.g.
+// "email" for "unknown email provider".
+func New[C, T any](kind string) *Registry[C, T] {
+	return &Registry[C, T]{
+		kind:      kind,
+		factories: map[string]func(C) (T, error){},
+	}
+}
+
+// Register makes a provider available by name, replacing any provider
+// registered under the same name
+func (r *Registry[C, T]) Register(name string, factory func(C) (T, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+}
+
+// Names lists the registered provider names
+func (r *Registry[C, T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds the named provider
+func (r *Registry[C, T]) New(name string, cfg C) (T, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unknown %s provider %q", r.kind, name)
+	}
+
+	return factory(cfg)
+}
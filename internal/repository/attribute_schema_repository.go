@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"auth-service/internal/domain"
+	"database/sql"
+	"fmt"
+)
+
+type attributeSchemaRepository struct {
+	db *sql.DB
+}
+
+func NewAttributeSchemaRepository(db *sql.DB) domain.AttributeSchemaRepository {
+	return &attributeSchemaRepository{
+		db: db,
+	}
+}
+
+func (r *attributeSchemaRepository) GetCurrent() (*domain.AttributeSchema, error) {
+	query := `
+        SELECT version, schema, COALESCE(created_by::text, ''), created_at
+        FROM user_attribute_schemas
+        ORDER BY version DESC
+        LIMIT 1
+    `
+
+	schema := &domain.AttributeSchema{}
+	err := r.db.QueryRow(query).Scan(&schema.Version, &schema.Schema, &schema.CreatedBy, &schema.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAttributeSchemaNotFound
+		}
+		return nil, fmt.Errorf("failed to get attribute schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+func (r *attributeSchemaRepository) Create(schema *domain.AttributeSchema) error {
+	query := `
+        INSERT INTO user_attribute_schemas (schema, created_by)
+        VALUES ($1, NULLIF($2, '')::uuid)
+        RETURNING version, created_at
+    `
+
+	err := r.db.QueryRow(query, []byte(schema.Schema), schema.CreatedBy).Scan(&schema.Version, &schema.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save attribute schema: %w", err)
+	}
+
+	return nil
+}
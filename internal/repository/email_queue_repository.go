@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"auth-service/internal/domain"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const outboundEmailColumns = `id, recipients, subject, payload, status, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at`
+
+type emailQueueRepository struct {
+	db *sql.DB
+}
+
+func NewEmailQueueRepository(db *sql.DB) domain.EmailQueueRepository {
+	return &emailQueueRepository{
+		db: db,
+	}
+}
+
+func scanOutboundEmail(row rowScanner) (*domain.OutboundEmail, error) {
+	email := &domain.OutboundEmail{}
+	err := row.Scan(
+		&email.ID,
+		&email.Recipients,
+		&email.Subject,
+		&email.Payload,
+		&email.Status,
+		&email.Attempts,
+		&email.LastError,
+		&email.NextAttemptAt,
+		&email.SentAt,
+		&email.CreatedAt,
+		&email.UpdatedAt,
+	)
+	return email, err
+}
+
+func (r *emailQueueRepository) Enqueue(email *domain.OutboundEmail) error {
+	query := `
+        INSERT INTO email_outbox (recipients, subject, payload)
+        VALUES ($1, $2, $3)
+        RETURNING ` + outboundEmailColumns
+
+	row := r.db.QueryRow(query, email.Recipients, email.Subject, email.Payload)
+	queued, err := scanOutboundEmail(row)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	*email = *queued
+	return nil
+}
+
+func (r *emailQueueRepository) ClaimDue(limit int, lease time.Duration) ([]*domain.OutboundEmail, error) {
+	// Attempts are counted when claimed so that a message which crashes
+	// its worker still runs out of retries
+	query := `
+        UPDATE email_outbox
+        SET status = 'sending',
+            attempts = attempts + 1,
+            locked_until = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second'
+        WHERE id IN (
+            SELECT id FROM email_outbox
+            WHERE (status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP)
+               OR (status = 'sending' AND locked_until <= CURRENT_TIMESTAMP)
+            ORDER BY next_attempt_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING ` + outboundEmailColumns
+
+	rows, err := r.db.Query(query, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []*domain.OutboundEmail{}
+	for rows.Next() {
+		email, err := scanOutboundEmail(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		emails = append(emails, email)
+	}
+
+	return emails, rows.Err()
+}
+
+func (r *emailQueueRepository) MarkSent(id string) error {
+	query := `
+        UPDATE email_outbox
+        SET status = 'sent', payload = NULL, last_error = '', locked_until = NULL, sent_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+    `
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to mark email sent: %w", err)
+	}
+	return nil
+}
+
+func (r *emailQueueRepository) MarkFailed(id, lastError string, next time.Time, dead bool) error {
+	status := domain.OutboundEmailPending
+	if dead {
+		status = domain.OutboundEmailDead
+	}
+
+	query := `
+        UPDATE email_outbox
+        SET status = $2, last_error = $3, next_attempt_at = $4, locked_until = NULL
+        WHERE id = $1
+    `
+
+	if _, err := r.db.Exec(query, id, status, lastError, next); err != nil {
+		return fmt.Errorf("failed to mark email failed: %w", err)
+	}
+	return nil
+}
+
+func (r *emailQueueRepository) GetByID(id string) (*domain.OutboundEmail, error) {
+	query := `SELECT ` + outboundEmailColumns + ` FROM email_outbox WHERE id = $1`
+
+	email, err := scanOutboundEmail(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to get email: %w", err)
+	}
+
+	return email, nil
+}
+
+func (r *emailQueueRepository) ListByStatus(status domain.OutboundEmailStatus, limit, offset int) ([]*domain.OutboundEmail, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM email_outbox WHERE status = $1`, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count emails: %w", err)
+	}
+
+	query := `
+        SELECT ` + outboundEmailColumns + `
+        FROM email_outbox
+        WHERE status = $1
+        ORDER BY updated_at DESC
+        LIMIT $2 OFFSET $3
+    `
+
+	rows, err := r.db.Query(query, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []*domain.OutboundEmail{}
+	for rows.Next() {
+		email, err := scanOutboundEmail(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan email: %w", err)
+		}
+		emails = append(emails, email)
+	}
+
+	return emails, total, rows.Err()
+}
+
+func (r *emailQueueRepository) Replay(id string) error {
+	query := `
+        UPDATE email_outbox
+        SET status = 'pending', attempts = 0, last_error = '', next_attempt_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'dead'
+    `
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to replay email: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := r.GetByID(id); err != nil {
+			return err
+		}
+		return domain.ErrEmailNotDead
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"auth-service/internal/cache"
+	"auth-service/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type cachedGroupRepository struct {
+	db    *sql.DB
+	cache cache.CacheService
+}
+
+func NewCachedGroupRepository(db *sql.DB, cache cache.CacheService) domain.GroupRepository {
+	return &cachedGroupRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+const (
+	userGroupsCacheDuration = 10 * time.Minute
+	userGroupsKey           = "user:groups:%s"
+
+	// groupHierarchyLock serializes edge inserts so two concurrent
+	// nestings cannot form a cycle that neither saw on its own
+	groupHierarchyLock = 7340301
+)
+
+// subtreeUsersQuery lists every user who is transitively a member of $1
+const subtreeUsersQuery = `
+    WITH RECURSIVE subtree(id) AS (
+        SELECT $1::uuid
+        UNION
+        SELECT gc.child_id FROM group_children gc JOIN subtree s ON gc.parent_id = s.id
+    )
+    SELECT DISTINCT gm.user_id FROM group_members gm JOIN subtree s ON gm.group_id = s.id
+`
+
+func (r *cachedGroupRepository) Create(group *domain.Group) error {
+	query := `
+        INSERT INTO groups (name, description)
+        VALUES ($1, $2)
+        RETURNING id, created_at, updated_at
+    `
+
+	err := r.db.QueryRow(query, group.Name, group.Description).Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrGroupAlreadyExists
+		}
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cachedGroupRepository) GetByID(id string) (*domain.Group, error) {
+	group := &domain.Group{}
+	query := `
+        SELECT g.id, g.name, g.description, g.created_at, g.updated_at,
+               COALESCE((SELECT array_agg(user_id::text) FROM group_members WHERE group_id = g.id), '{}'),
+               COALESCE((SELECT array_agg(child_id::text) FROM group_children WHERE parent_id = g.id), '{}')
+        FROM groups g
+        WHERE g.id = $1
+    `
+
+	err := r.db.QueryRow(query, id).Scan(
+		&group.ID,
+		&group.Name,
+		&group.Description,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+		pq.Array(&group.Members),
+		pq.Array(&group.Children),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return group, nil
+}
+
+func (r *cachedGroupRepository) List() ([]*domain.Group, error) {
+	rows, err := r.db.Query(`SELECT id, name, description, created_at, updated_at FROM groups ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []*domain.Group{}
+	for rows.Next() {
+		group := &domain.Group{}
+		if err := rows.Scan(&group.ID, &group.Name, &group.Description, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+func (r *cachedGroupRepository) Delete(id string) error {
+	affected, err := r.subtreeUsers(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`DELETE FROM groups WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrGroupNotFound
+	}
+
+	r.invalidateUsers(affected)
+	return nil
+}
+
+func (r *cachedGroupRepository) AddMember(groupID, userID string) error {
+	query := `INSERT INTO group_members (group_id, user_id) VALUES ($1, $2)`
+
+	if _, err := r.db.Exec(query, groupID, userID); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyInGroup
+		}
+		if isForeignKeyViolation(err) {
+			return domain.ErrGroupNotFound
+		}
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+
+	r.invalidateUsers([]string{userID})
+	return nil
+}
+
+func (r *cachedGroupRepository) RemoveMember(groupID, userID string) error {
+	query := `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(query, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotInGroup
+	}
+
+	r.invalidateUsers([]string{userID})
+	return nil
+}
+
+// AddChild nests childID under parentID, refusing edges that would make a
+// group its own ancestor
+func (r *cachedGroupRepository) AddChild(parentID, childID string) error {
+	if parentID == childID {
+		return domain.ErrGroupCycle
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, groupHierarchyLock); err != nil {
+		return fmt.Errorf("failed to lock group hierarchy: %w", err)
+	}
+
+	// The edge closes a cycle if the parent is already below the child
+	var cycle bool
+	query := `
+        WITH RECURSIVE descendants(id) AS (
+            SELECT child_id FROM group_children WHERE parent_id = $1
+            UNION
+            SELECT gc.child_id FROM group_children gc JOIN descendants d ON gc.parent_id = d.id
+        )
+        SELECT EXISTS (SELECT 1 FROM descendants WHERE id = $2)
+    `
+	if err := tx.QueryRow(query, childID, parentID).Scan(&cycle); err != nil {
+		return fmt.Errorf("failed to check group cycle: %w", err)
+	}
+	if cycle {
+		return domain.ErrGroupCycle
+	}
+
+	if _, err := tx.Exec(`INSERT INTO group_children (parent_id, child_id) VALUES ($1, $2)`, parentID, childID); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyInGroup
+		}
+		if isForeignKeyViolation(err) {
+			return domain.ErrGroupNotFound
+		}
+		return fmt.Errorf("failed to nest group: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group nesting: %w", err)
+	}
+
+	return r.invalidateSubtree(childID)
+}
+
+func (r *cachedGroupRepository) RemoveChild(parentID, childID string) error {
+	query := `DELETE FROM group_children WHERE parent_id = $1 AND child_id = $2`
+
+	result, err := r.db.Exec(query, parentID, childID)
+	if err != nil {
+		return fmt.Errorf("failed to remove nested group: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotInGroup
+	}
+
+	return r.invalidateSubtree(childID)
+}
+
+// GetUserGroups returns the names of every group the user belongs to,
+// directly or through nesting
+func (r *cachedGroupRepository) GetUserGroups(userID string) ([]string, error) {
+	var groups []string
+	err := r.cache.GetOrSet(context.Background(), fmt.Sprintf(userGroupsKey, userID), &groups, userGroupsCacheDuration, func() (interface{}, error) {
+		query := `
+            WITH RECURSIVE ancestors(id) AS (
+                SELECT group_id FROM group_members WHERE user_id = $1
+                UNION
+                SELECT gc.parent_id FROM group_children gc JOIN ancestors a ON gc.child_id = a.id
+            )
+            SELECT g.name FROM groups g JOIN ancestors a ON g.id = a.id ORDER BY g.name
+        `
+		return r.queryStrings(query, userID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+func (r *cachedGroupRepository) subtreeUsers(groupID string) ([]string, error) {
+	return r.queryStrings(subtreeUsersQuery, groupID)
+}
+
+// invalidateSubtree drops cached groups for everyone below groupID, whose
+// ancestor set just changed
+func (r *cachedGroupRepository) invalidateSubtree(groupID string) error {
+	userIDs, err := r.subtreeUsers(groupID)
+	if err != nil {
+		return err
+	}
+
+	r.invalidateUsers(userIDs)
+	return nil
+}
+
+func (r *cachedGroupRepository) invalidateUsers(userIDs []string) {
+	ctx := context.Background()
+	for _, userID := range userIDs {
+		_ = r.cache.Delete(ctx, fmt.Sprintf(userGroupsKey, userID))
+	}
+}
+
+func (r *cachedGroupRepository) queryStrings(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"auth-service/internal/domain"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type invitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) domain.InvitationRepository {
+	return &invitationRepository{
+		db: db,
+	}
+}
+
+const invitationColumns = `i.id, i.organization_id, o.name, i.email, i.role, COALESCE(i.invited_by::text, ''),
+            i.expires_at, i.accepted_at, i.revoked_at, i.created_at`
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	invitation := &domain.Invitation{}
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.OrganizationID,
+		&invitation.OrganizationName,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.InvitedBy,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.RevokedAt,
+		&invitation.CreatedAt,
+	)
+	return invitation, err
+}
+
+func (r *invitationRepository) Create(invitation *domain.Invitation) error {
+	query := `
+        INSERT INTO invitations (organization_id, email, role, invited_by, expires_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+
+	err := r.db.QueryRow(
+		query,
+		invitation.OrganizationID,
+		invitation.Email,
+		invitation.Role,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	).Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrOrganizationNotFound
+		}
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *invitationRepository) GetByID(id string) (*domain.Invitation, error) {
+	query := `
+        SELECT ` + invitationColumns + `
+        FROM invitations i
+        JOIN organizations o ON o.id = i.organization_id
+        WHERE i.id = $1
+    `
+
+	invitation, err := scanInvitation(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+func (r *invitationRepository) ListByOrganization(orgID string) ([]*domain.Invitation, error) {
+	query := `
+        SELECT ` + invitationColumns + `
+        FROM invitations i
+        JOIN organizations o ON o.id = i.organization_id
+        WHERE i.organization_id = $1
+        ORDER BY i.created_at DESC
+    `
+
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []*domain.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+// RevokePending revokes any open invitation for the email, so re-inviting
+// someone leaves only the newest link usable
+func (r *invitationRepository) RevokePending(orgID, email string) error {
+	query := `
+        UPDATE invitations
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE organization_id = $1 AND email = $2
+          AND accepted_at IS NULL AND revoked_at IS NULL
+    `
+
+	if _, err := r.db.Exec(query, orgID, email); err != nil {
+		return fmt.Errorf("failed to revoke invitations: %w", err)
+	}
+
+	return nil
+}
+
+func (r *invitationRepository) Revoke(orgID, id string) error {
+	query := `
+        UPDATE invitations
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND organization_id = $2
+          AND accepted_at IS NULL AND revoked_at IS NULL
+    `
+
+	result, err := r.db.Exec(query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// Claim marks a pending invitation accepted. It only succeeds once, and not
+// after the invitation was revoked or expired, so concurrent accepts cannot
+// both win.
+func (r *invitationRepository) Claim(invitation *domain.Invitation) error {
+	query := `
+        UPDATE invitations
+        SET accepted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+          AND expires_at > CURRENT_TIMESTAMP
+        RETURNING accepted_at
+    `
+
+	var acceptedAt time.Time
+	if err := r.db.QueryRow(query, invitation.ID).Scan(&acceptedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrInvitationInvalid
+		}
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	invitation.AcceptedAt = &acceptedAt
+	return nil
+}
+
+// Release returns a claimed invitation to pending when the account it was
+// claimed for could not be set up
+func (r *invitationRepository) Release(id string) error {
+	query := `
+        UPDATE invitations
+        SET accepted_at = NULL
+        WHERE id = $1 AND accepted_at IS NOT NULL
+    `
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to release invitation: %w", err)
+	}
+
+	return nil
+}
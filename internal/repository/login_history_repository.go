@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"auth-service/internal/domain"
+	"database/sql"
+	"fmt"
+)
+
+type loginHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewLoginHistoryRepository(db *sql.DB) domain.LoginHistoryRepository {
+	return &loginHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *loginHistoryRepository) Record(event *domain.LoginEvent) error {
+	query := `
+        INSERT INTO login_events (user_id, email, method, success, failure_reason, ip_address, user_agent, device_fingerprint)
+        VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, created_at
+    `
+
+	err := r.db.QueryRow(
+		query,
+		event.UserID,
+		event.Email,
+		event.Method,
+		event.Success,
+		event.FailureReason,
+		event.IPAddress,
+		event.UserAgent,
+		event.DeviceFingerprint,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record login event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *loginHistoryRepository) ListByUser(userID string, limit, offset int) ([]*domain.LoginEvent, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM login_events WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count login events: %w", err)
+	}
+
+	query := `
+        SELECT id, COALESCE(user_id::text, ''), email, method, success, failure_reason,
+               ip_address, user_agent, device_fingerprint, created_at
+        FROM login_events
+        WHERE user_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3
+    `
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list login events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.LoginEvent{}
+	for rows.Next() {
+		event := &domain.LoginEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.Email,
+			&event.Method,
+			&event.Success,
+			&event.FailureReason,
+			&event.IPAddress,
+			&event.UserAgent,
+			&event.DeviceFingerprint,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan login event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, total, rows.Err()
+}
+
+func (r *loginHistoryRepository) LastSuccess(userID string) (*domain.LoginEvent, error) {
+	query := `
+        SELECT id, ip_address, user_agent, created_at
+        FROM login_events
+        WHERE user_id = $1 AND success
+        ORDER BY created_at DESC
+        LIMIT 1
+    `
+
+	event := &domain.LoginEvent{UserID: userID, Success: true}
+	err := r.db.QueryRow(query, userID).Scan(&event.ID, &event.IPAddress, &event.UserAgent, &event.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrLoginEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get last login: %w", err)
+	}
+
+	return event, nil
+}
+
+func (r *loginHistoryRepository) DeviceSeen(userID, fingerprint string) (bool, bool, error) {
+	query := `
+        SELECT
+            EXISTS (SELECT 1 FROM login_events WHERE user_id = $1 AND success AND device_fingerprint = $2),
+            EXISTS (SELECT 1 FROM login_events WHERE user_id = $1 AND success)
+    `
+
+	var seen, hasHistory bool
+	if err := r.db.QueryRow(query, userID, fingerprint).Scan(&seen, &hasHistory); err != nil {
+		return false, false, fmt.Errorf("failed to check known devices: %w", err)
+	}
+
+	return seen, hasHistory, nil
+}
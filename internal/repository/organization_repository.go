@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"auth-service/internal/cache"
+	"auth-service/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type cachedOrganizationRepository struct {
+	db    *sql.DB
+	cache cache.CacheService
+}
+
+func NewCachedOrganizationRepository(db *sql.DB, cache cache.CacheService) domain.OrganizationRepository {
+	return &cachedOrganizationRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+const (
+	membershipCacheDuration = 5 * time.Minute
+	userMembershipsKey      = "user:memberships:%s"
+)
+
+// Create inserts the organization and makes ownerID its first owner
+func (r *cachedOrganizationRepository) Create(org *domain.Organization, ownerID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO organizations (name, slug, created_by)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at, updated_at
+    `
+	if err := tx.QueryRow(query, org.Name, org.Slug, ownerID).Scan(&org.ID, &org.CreatedAt, &org.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrSlugTaken
+		}
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	org.CreatedBy = ownerID
+
+	if _, err := tx.Exec(
+		`INSERT INTO memberships (organization_id, user_id, role) VALUES ($1, $2, $3)`,
+		org.ID, ownerID, domain.OrgRoleOwner,
+	); err != nil {
+		return fmt.Errorf("failed to add owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit organization: %w", err)
+	}
+
+	r.invalidateUser(ownerID)
+	return nil
+}
+
+func (r *cachedOrganizationRepository) GetByID(id string) (*domain.Organization, error) {
+	org := &domain.Organization{}
+	query := `
+        SELECT id, name, slug, COALESCE(created_by::text, ''), created_at, updated_at
+        FROM organizations
+        WHERE id = $1
+    `
+
+	err := r.db.QueryRow(query, id).Scan(
+		&org.ID,
+		&org.Name,
+		&org.Slug,
+		&org.CreatedBy,
+		&org.CreatedAt,
+		&org.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return org, nil
+}
+
+// GetMembership is served from the user's cached membership list, since
+// tenant-scoped requests look it up on every call
+func (r *cachedOrganizationRepository) GetMembership(orgID, userID string) (*domain.Membership, error) {
+	memberships, err := r.ListMembershipsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, membership := range memberships {
+		if membership.OrganizationID == orgID {
+			return membership, nil
+		}
+	}
+
+	return nil, domain.ErrNotMember
+}
+
+func (r *cachedOrganizationRepository) ListMembershipsByUser(userID string) ([]*domain.Membership, error) {
+	var memberships []*domain.Membership
+	err := r.cache.GetOrSet(context.Background(), fmt.Sprintf(userMembershipsKey, userID), &memberships, membershipCacheDuration, func() (interface{}, error) {
+		return r.queryMemberships(`
+            SELECT m.organization_id, o.name, o.slug, m.user_id, u.email, u.name, m.role, m.created_at
+            FROM memberships m
+            JOIN organizations o ON o.id = m.organization_id
+            JOIN users u ON u.id = m.user_id
+            WHERE m.user_id = $1
+            ORDER BY o.name
+        `, userID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	return memberships, nil
+}
+
+func (r *cachedOrganizationRepository) ListMembers(orgID string) ([]*domain.Membership, error) {
+	return r.queryMemberships(`
+        SELECT m.organization_id, o.name, o.slug, m.user_id, u.email, u.name, m.role, m.created_at
+        FROM memberships m
+        JOIN organizations o ON o.id = m.organization_id
+        JOIN users u ON u.id = m.user_id
+        WHERE m.organization_id = $1 AND NOT u.is_deleted
+        ORDER BY m.created_at
+    `, orgID)
+}
+
+func (r *cachedOrganizationRepository) AddMember(orgID, userID string, role domain.OrgRole) error {
+	query := `INSERT INTO memberships (organization_id, user_id, role) VALUES ($1, $2, $3)`
+
+	if _, err := r.db.Exec(query, orgID, userID, role); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyMember
+		}
+		if isForeignKeyViolation(err) {
+			return domain.ErrOrganizationNotFound
+		}
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+
+	r.invalidateUser(userID)
+	return nil
+}
+
+func (r *cachedOrganizationRepository) UpdateMemberRole(orgID, userID string, role domain.OrgRole) error {
+	query := `UPDATE memberships SET role = $1 WHERE organization_id = $2 AND user_id = $3`
+
+	result, err := r.db.Exec(query, role, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotMember
+	}
+
+	r.invalidateUser(userID)
+	return nil
+}
+
+func (r *cachedOrganizationRepository) RemoveMember(orgID, userID string) error {
+	query := `DELETE FROM memberships WHERE organization_id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(query, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotMember
+	}
+
+	r.invalidateUser(userID)
+	return nil
+}
+
+func (r *cachedOrganizationRepository) CountOwners(orgID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM memberships WHERE organization_id = $1 AND role = 'owner'`
+
+	if err := r.db.QueryRow(query, orgID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count owners: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *cachedOrganizationRepository) queryMemberships(query string, args ...interface{}) ([]*domain.Membership, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	memberships := []*domain.Membership{}
+	for rows.Next() {
+		membership := &domain.Membership{}
+		if err := rows.Scan(
+			&membership.OrganizationID,
+			&membership.OrganizationName,
+			&membership.OrganizationSlug,
+			&membership.UserID,
+			&membership.UserEmail,
+			&membership.UserName,
+			&membership.Role,
+			&membership.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, rows.Err()
+}
+
+func (r *cachedOrganizationRepository) invalidateUser(userID string) {
+	_ = r.cache.Delete(context.Background(), fmt.Sprintf(userMembershipsKey, userID))
+}
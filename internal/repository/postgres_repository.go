@@ -5,7 +5,10 @@ import (
 	"auth-service/internal/domain"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -27,26 +30,65 @@ const (
 	userByEmailKey    = "user:email:%s"
 )
 
+const userColumns = `id, email, pending_email, username, name, locale, timezone, avatar_url, phone_number, phone_verified_at,
+            email_verified_at, is_active, status, suspension_reason, suspended_until, password_reset_required, is_deleted, deleted_at,
+            deleted_by, status_before_delete, attributes, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	user := &domain.User{}
+	var deletedBy, statusBeforeDelete sql.NullString
+	var attributes []byte
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PendingEmail,
+		&user.Username,
+		&user.Name,
+		&user.Locale,
+		&user.Timezone,
+		&user.AvatarURL,
+		&user.PhoneNumber,
+		&user.PhoneVerifiedAt,
+		&user.EmailVerifiedAt,
+		&user.IsActive,
+		&user.Status,
+		&user.SuspensionReason,
+		&user.SuspendedUntil,
+		&user.PasswordResetRequired,
+		&user.IsDeleted,
+		&user.DeletedAt,
+		&deletedBy,
+		&statusBeforeDelete,
+		&attributes,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return user, err
+	}
+	user.DeletedBy = deletedBy.String
+	user.StatusBeforeDelete = domain.UserStatus(statusBeforeDelete.String)
+
+	if len(attributes) > 0 {
+		if err := json.Unmarshal(attributes, &user.Attributes); err != nil {
+			return user, fmt.Errorf("failed to decode user attributes: %w", err)
+		}
+	}
+	return user, nil
+}
+
 func (r *cachedUserRepository) GetByID(id string) (*domain.User, error) {
 	ctx := context.Background()
 	cacheKey := fmt.Sprintf(userByIDKey, id)
 
 	var user domain.User
 	err := r.cache.GetOrSet(ctx, cacheKey, &user, userCacheDuration, func() (interface{}, error) {
-		user := &domain.User{}
-		query := `
-            SELECT id, email, name, is_active, created_at, updated_at
-            FROM users 
-            WHERE id = $1
-        `
-		err := r.db.QueryRow(query, id).Scan(
-			&user.ID,
-			&user.Email,
-			&user.Name,
-			&user.IsActive,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND NOT is_deleted`
+		user, err := scanUser(r.db.QueryRow(query, id))
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return nil, domain.ErrUserNotFound
@@ -57,8 +99,8 @@ func (r *cachedUserRepository) GetByID(id string) (*domain.User, error) {
 	})
 
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, err
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -69,38 +111,23 @@ func (r *cachedUserRepository) GetByID(id string) (*domain.User, error) {
 func (r *cachedUserRepository) GetByEmail(email string) (*domain.User, error) {
 	ctx := context.Background()
 	cacheKey := fmt.Sprintf(userByEmailKey, email)
+
 	var user domain.User
 	err := r.cache.GetOrSet(ctx, cacheKey, &user, userCacheDuration, func() (interface{}, error) {
-		user := &domain.User{}
-		fmt.Println(user)
-
-		query := `
-            SELECT id, email, name, password, is_active, created_at, updated_at
-            FROM users 
-            WHERE email = $1
-        `
-		err := r.db.QueryRow(query, email).Scan(
-			&user.ID,
-			&user.Email,
-			&user.Name,
-			&user.Password,
-			&user.IsActive,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		query := `SELECT ` + userColumns + ` FROM users WHERE email = $1 AND NOT is_deleted`
+		user, err := scanUser(r.db.QueryRow(query, email))
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return nil, domain.ErrUserNotFound
 			}
 			return nil, fmt.Errorf("database error: %w", err)
 		}
-		fmt.Println(user)
 		return user, nil
 	})
 
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, err
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -108,6 +135,108 @@ func (r *cachedUserRepository) GetByEmail(email string) (*domain.User, error) {
 	return &user, nil
 }
 
+// GetByIDIncludingDeleted also returns soft-deleted users. It bypasses the
+// cache, which only ever holds visible users.
+func (r *cachedUserRepository) GetByIDIncludingDeleted(id string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	user, err := scanUser(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByEmailIncludingDeleted also returns soft-deleted users. Since a deleted
+// user's email can be registered again, the visible account wins, followed
+// by the most recently deleted one.
+func (r *cachedUserRepository) GetByEmailIncludingDeleted(email string) (*domain.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM users 
+        WHERE email = $1
+        ORDER BY is_deleted, deleted_at DESC
+        LIMIT 1
+    `
+	user, err := scanUser(r.db.QueryRow(query, email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByNormalizedEmail bypasses the cache, which is keyed by the address as
+// registered
+func (r *cachedUserRepository) GetByNormalizedEmail(normalizedEmail string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE normalized_email = $1 AND NOT is_deleted`
+	user, err := scanUser(r.db.QueryRow(query, normalizedEmail))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByNormalizedEmailIncludingDeleted matches the canonical address the
+// same way, preferring the visible account, then the most recently deleted
+func (r *cachedUserRepository) GetByNormalizedEmailIncludingDeleted(normalizedEmail string) (*domain.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM users 
+        WHERE normalized_email = $1
+        ORDER BY is_deleted, deleted_at DESC
+        LIMIT 1
+    `
+	user, err := scanUser(r.db.QueryRow(query, normalizedEmail))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByUsername bypasses the cache, which is keyed by ID and email
+func (r *cachedUserRepository) GetByUsername(username string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(username) = lower($1) AND username <> '' AND NOT is_deleted`
+	user, err := scanUser(r.db.QueryRow(query, username))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetPasswordHash reads the password hash straight from the database.
+// Hashes are never cached since User.Password is not serialized.
+func (r *cachedUserRepository) GetPasswordHash(id string) (string, error) {
+	var hash string
+	err := r.db.QueryRow(`SELECT password FROM users WHERE id = $1`, id).Scan(&hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to get password: %w", err)
+	}
+
+	return hash, nil
+}
+
 func (r *cachedUserRepository) Create(user *domain.User) error {
 	ctx := context.Background()
 
@@ -116,13 +245,13 @@ func (r *cachedUserRepository) Create(user *domain.User) error {
 	if err == nil {
 		return domain.ErrUserAlreadyExists
 	}
-	if err != domain.ErrUserNotFound {
+	if !errors.Is(err, domain.ErrUserNotFound) {
 		return fmt.Errorf("failed to check existing user: %w", err)
 	}
 
 	query := `
-        INSERT INTO users (id, email, name, password, is_active, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO users (id, email, normalized_email, username, name, locale, password, is_active, status, email_verified_at, created_at, updated_at)
+        VALUES ($1, $2, COALESCE(NULLIF($3, ''), lower($2)), $4, $5, $6, $7, $8, $9, $10, $11, $12)
         RETURNING id
     `
 
@@ -130,12 +259,20 @@ func (r *cachedUserRepository) Create(user *domain.User) error {
 		query,
 		user.ID,
 		user.Email,
+		user.NormalizedEmail,
+		user.Username,
 		user.Name,
+		user.Locale,
 		user.Password,
 		user.IsActive,
+		user.Status,
+		user.EmailVerifiedAt,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrUserAlreadyExists
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -146,18 +283,116 @@ func (r *cachedUserRepository) Create(user *domain.User) error {
 	return nil
 }
 
-func (r *cachedUserRepository) UpdateActive(id string, isActive bool) error {
+// MarkEmailVerified only matches accounts that have never been verified, so
+// a deactivated or suspended account cannot verify its way back in
+func (r *cachedUserRepository) MarkEmailVerified(id string) error {
+	ctx := context.Background()
+
+	query := `
+        UPDATE users 
+        SET is_active = true,
+            status = 'active',
+            email_verified_at = CURRENT_TIMESTAMP,
+            updated_at = CURRENT_TIMESTAMP 
+        WHERE id = $1 AND status = 'inactive' AND email_verified_at IS NULL AND NOT is_deleted
+        RETURNING email
+    `
+
+	var email string
+	err := r.db.QueryRow(query, id).Scan(&email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrOTPAlreadyVerified
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, email))
+
+	return nil
+}
+
+func (r *cachedUserRepository) UpdateStatus(id string, change domain.StatusChange) error {
 	ctx := context.Background()
 
+	reason, until := "", change.Until
+	if change.Status == domain.UserStatusSuspended {
+		reason = change.Reason
+	} else {
+		until = nil
+	}
+
+	// An administrator activating an unverified account vouches for its email
 	query := `
         UPDATE users 
-        SET is_active = $1, updated_at = CURRENT_TIMESTAMP 
+        SET status = $1,
+            is_active = ($1 = 'active'),
+            email_verified_at = CASE WHEN $1 = 'active' THEN COALESCE(email_verified_at, CURRENT_TIMESTAMP) ELSE email_verified_at END,
+            suspension_reason = $2,
+            suspended_until = $3,
+            updated_at = CURRENT_TIMESTAMP 
+        WHERE id = $4
+        RETURNING email
+    `
+
+	var email string
+	err := r.db.QueryRow(query, change.Status, reason, until, id).Scan(&email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, email))
+
+	return nil
+}
+
+func (r *cachedUserRepository) UpdatePassword(id string, hashedPassword string) error {
+	ctx := context.Background()
+
+	query := `
+        UPDATE users 
+        SET password = $1,
+            password_reset_required = FALSE,
+            updated_at = CURRENT_TIMESTAMP 
         WHERE id = $2
         RETURNING email
     `
 
 	var email string
-	err := r.db.QueryRow(query, isActive, id).Scan(&email)
+	err := r.db.QueryRow(query, hashedPassword, id).Scan(&email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, email))
+
+	return nil
+}
+
+func (r *cachedUserRepository) SetPasswordResetRequired(id string, required bool) error {
+	ctx := context.Background()
+
+	query := `
+        UPDATE users 
+        SET password_reset_required = $1, updated_at = CURRENT_TIMESTAMP 
+        WHERE id = $2
+        RETURNING email
+    `
+
+	var email string
+	err := r.db.QueryRow(query, required, id).Scan(&email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.ErrUserNotFound
@@ -172,12 +407,211 @@ func (r *cachedUserRepository) UpdateActive(id string, isActive bool) error {
 	return nil
 }
 
-func (r *cachedUserRepository) SoftDelete(id string) error {
+func (r *cachedUserRepository) SetPhoneNumber(id, phoneNumber string) error {
+	ctx := context.Background()
+
+	query := `
+        UPDATE users
+        SET phone_number = $1,
+            phone_verified_at = CASE WHEN $1 = '' THEN NULL ELSE CURRENT_TIMESTAMP END,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND NOT is_deleted
+        RETURNING email
+    `
+
+	var email string
+	err := r.db.QueryRow(query, phoneNumber, id).Scan(&email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return domain.ErrPhoneNumberTaken
+		}
+		return fmt.Errorf("failed to update phone number: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, email))
+
+	return nil
+}
+
+func (r *cachedUserRepository) UpdateProfile(user *domain.User) error {
+	ctx := context.Background()
+
+	attributes, err := json.Marshal(user.Attributes)
+	if err != nil {
+		return fmt.Errorf("failed to encode user attributes: %w", err)
+	}
+	if user.Attributes == nil {
+		attributes = []byte("{}")
+	}
+
+	query := `
+        UPDATE users
+        SET name = $1, username = $2, locale = $3, timezone = $4, avatar_url = $5, attributes = $6,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $7 AND NOT is_deleted
+        RETURNING email, updated_at
+    `
+
+	var email string
+	err = r.db.QueryRow(query, user.Name, user.Username, user.Locale, user.Timezone, user.AvatarURL, attributes, user.ID).Scan(&email, &user.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameTaken
+		}
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, user.ID))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, email))
+
+	return nil
+}
+
+func (r *cachedUserRepository) SetPendingEmail(id, email string) error {
+	ctx := context.Background()
+
+	query := `
+        UPDATE users
+        SET pending_email = $1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND NOT is_deleted
+        RETURNING email
+    `
+
+	var currentEmail string
+	err := r.db.QueryRow(query, email, id).Scan(&currentEmail)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to update pending email: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, currentEmail))
+
+	return nil
+}
+
+func (r *cachedUserRepository) ConfirmEmailChange(id, pendingEmail, normalizedEmail string) (string, error) {
+	ctx := context.Background()
+
+	// Lock the row so the old address returned is the one replaced. The
+	// unique indexes on visible emails settle races with other accounts.
+	query := `
+        WITH previous AS (
+            SELECT id, email FROM users WHERE id = $1 AND NOT is_deleted FOR UPDATE
+        )
+        UPDATE users
+        SET email = users.pending_email, normalized_email = $3, pending_email = '', updated_at = CURRENT_TIMESTAMP
+        FROM previous
+        WHERE users.id = previous.id AND users.pending_email = $2 AND $2 <> ''
+        RETURNING previous.email
+    `
+
+	var oldEmail string
+	err := r.db.QueryRow(query, id, pendingEmail, normalizedEmail).Scan(&oldEmail)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrNoPendingEmail
+		}
+		if isUniqueViolation(err) {
+			return "", domain.ErrUserAlreadyExists
+		}
+		return "", fmt.Errorf("failed to change email: %w", err)
+	}
+
+	// Invalidate cache entries under both addresses
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, oldEmail))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, pendingEmail))
+
+	return oldEmail, nil
+}
+
+// List returns a page of users matching the filter along with the total match count.
+// Listings bypass the cache since they are admin-only and change frequently.
+func (r *cachedUserRepository) List(filter domain.UserFilter) ([]*domain.User, int, error) {
+	conditions := []string{"TRUE"}
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "NOT is_deleted")
+	}
+	args := []interface{}{}
+
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Email != "" {
+		addCondition("email ILIKE $%d", "%"+escapeLike(filter.Email)+"%")
+	}
+	if filter.Name != "" {
+		addCondition("name ILIKE $%d", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.CreatedAfter != nil {
+		addCondition("created_at >= $%d", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		addCondition("created_at <= $%d", *filter.CreatedBefore)
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM users "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM users 
+        %s
+        ORDER BY created_at DESC, id
+        LIMIT $%d OFFSET $%d
+    `, userColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+func (r *cachedUserRepository) SoftDelete(id, actorID string) error {
 	ctx := context.Background()
 
 	query := `
         UPDATE users 
         SET is_deleted = true,
+            is_active = false,
+            status_before_delete = status,
+            status = 'deleted',
+            deleted_by = NULLIF($2, '')::uuid,
             deleted_at = CURRENT_TIMESTAMP,
             updated_at = CURRENT_TIMESTAMP
         WHERE id = $1 AND NOT is_deleted
@@ -185,7 +619,7 @@ func (r *cachedUserRepository) SoftDelete(id string) error {
     `
 
 	var email string
-	err := r.db.QueryRow(query, id).Scan(&email)
+	err := r.db.QueryRow(query, id, actorID).Scan(&email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.ErrUserNotFound
@@ -200,6 +634,79 @@ func (r *cachedUserRepository) SoftDelete(id string) error {
 	return nil
 }
 
+func (r *cachedUserRepository) Restore(id string) (domain.UserStatus, error) {
+	ctx := context.Background()
+
+	// Accounts deleted before their prior status was recorded come back
+	// active. A username claimed by someone else while the account was
+	// deleted stays with them, and the restored account goes without.
+	query := `
+        UPDATE users 
+        SET is_deleted = false,
+            is_active = COALESCE(status_before_delete, 'active') = 'active',
+            status = COALESCE(status_before_delete, 'active'),
+            status_before_delete = NULL,
+            deleted_by = NULL,
+            deleted_at = NULL,
+            username = CASE WHEN EXISTS (
+                SELECT 1 FROM users other
+                WHERE lower(other.username) = lower(users.username) AND other.username <> ''
+                  AND other.id <> users.id AND NOT other.is_deleted
+            ) THEN '' ELSE username END,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND is_deleted
+        RETURNING email, status
+    `
+
+	var email string
+	var status domain.UserStatus
+	err := r.db.QueryRow(query, id).Scan(&email, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrUserNotFound
+		}
+		// The email was registered again after this account was deleted
+		if isUniqueViolation(err) {
+			return "", domain.ErrUserAlreadyExists
+		}
+		return "", fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	// Invalidate cache entries
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, id))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, email))
+
+	return status, nil
+}
+
+// ListDeletedBefore returns soft-deleted users whose deletion is older than cutoff
+func (r *cachedUserRepository) ListDeletedBefore(cutoff time.Time, limit int) ([]*domain.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM users 
+        WHERE is_deleted AND deleted_at < $1
+        ORDER BY deleted_at
+        LIMIT $2
+    `
+
+	rows, err := r.db.Query(query, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
 func (r *cachedUserRepository) HardDelete(id string) error {
 	ctx := context.Background()
 
@@ -231,3 +738,75 @@ func (r *cachedUserRepository) HardDelete(id string) error {
 
 	return nil
 }
+
+// NormalizeEmails recomputes canonical addresses in creation order, so the
+// older account keeps a shared mailbox. Blocked updates are retried while
+// a pass makes progress, since another account may be moving off the
+// address in the same run.
+func (r *cachedUserRepository) NormalizeEmails(normalize func(email string) string) ([]domain.EmailConflict, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(`SELECT id, email, normalized_email FROM users ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list emails: %w", err)
+	}
+
+	type staleEmail struct {
+		id, email, normalized string
+	}
+	var stale []staleEmail
+	for rows.Next() {
+		var id, email, current string
+		if err := rows.Scan(&id, &email, &current); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		if normalized := normalize(email); normalized != current {
+			stale = append(stale, staleEmail{id: id, email: email, normalized: normalized})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list emails: %w", err)
+	}
+
+	for len(stale) > 0 {
+		var blocked []staleEmail
+		for _, s := range stale {
+			_, err := r.db.Exec(`UPDATE users SET normalized_email = $2 WHERE id = $1`, s.id, s.normalized)
+			if isUniqueViolation(err) {
+				blocked = append(blocked, s)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to normalize email: %w", err)
+			}
+
+			_ = r.cache.Delete(ctx, fmt.Sprintf(userByIDKey, s.id))
+			_ = r.cache.Delete(ctx, fmt.Sprintf(userByEmailKey, s.email))
+		}
+
+		progressed := len(blocked) < len(stale)
+		stale = blocked
+		if !progressed {
+			break
+		}
+	}
+
+	conflicts := make([]domain.EmailConflict, 0, len(stale))
+	for _, s := range stale {
+		conflict := domain.EmailConflict{UserID: s.id, Email: s.email, NormalizedEmail: s.normalized}
+		query := `SELECT id FROM users WHERE normalized_email = $1 AND NOT is_deleted AND id <> $2 LIMIT 1`
+		if err := r.db.QueryRow(query, s.normalized, s.id).Scan(&conflict.HeldBy); err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to find conflicting account: %w", err)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, nil
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
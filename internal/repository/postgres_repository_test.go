@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/emailaddress"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// memoryCache stores values as JSON like the Redis cache does, so cached
+// reads go through the same encoding as in production
+type memoryCache struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{items: map[string][]byte{}}
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = data
+	return nil
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	data, ok := c.items[key]
+	c.mu.Unlock()
+	if !ok {
+		return errors.New("cache miss")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+	return nil
+}
+
+func (c *memoryCache) GetOrSet(ctx context.Context, key string, dest interface{}, ttl time.Duration, fn func() (interface{}, error)) error {
+	if err := c.Get(ctx, key, dest); err == nil {
+		return nil
+	}
+	data, err := fn()
+	if err != nil {
+		return err
+	}
+	if err := c.Set(ctx, key, data, ttl); err != nil {
+		return err
+	}
+	return c.Get(ctx, key, dest)
+}
+
+// newTestUserRepository migrates the database named by TEST_DATABASE_URL and
+// returns a repository on it. Tests are skipped when the variable is unset.
+func newTestUserRepository(t *testing.T) domain.UserRepository {
+	t.Helper()
+
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	m, err := migrate.New("file://../../migrations", dbURL)
+	if err != nil {
+		t.Fatalf("failed to initialize migrations: %v", err)
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	m.Close()
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewCachedUserRepository(db, newMemoryCache())
+}
+
+func createTestUser(t *testing.T, repo domain.UserRepository, email string) *domain.User {
+	t.Helper()
+
+	now := time.Now()
+	user := &domain.User{
+		ID:        uuid.New().String(),
+		Email:     email,
+		Name:      "Test User",
+		Password:  "hash",
+		IsActive:  true,
+		Status:    domain.UserStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.HardDelete(user.ID) })
+	return user
+}
+
+func testEmail() string {
+	return "user-" + uuid.New().String() + "@example.com"
+}
+
+func TestSoftDeletedUserIsHiddenFromReads(t *testing.T) {
+	repo := newTestUserRepository(t)
+	user := createTestUser(t, repo, testEmail())
+
+	// Warm the cache so the test also covers invalidation on delete
+	if _, err := repo.GetByID(user.ID); err != nil {
+		t.Fatalf("GetByID before delete: %v", err)
+	}
+	if _, err := repo.GetByEmail(user.Email); err != nil {
+		t.Fatalf("GetByEmail before delete: %v", err)
+	}
+
+	if err := repo.SoftDelete(user.ID, user.ID); err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() (*domain.User, error)
+	}{
+		{"GetByID", func() (*domain.User, error) { return repo.GetByID(user.ID) }},
+		{"GetByEmail", func() (*domain.User, error) { return repo.GetByEmail(user.Email) }},
+		{"GetByNormalizedEmail", func() (*domain.User, error) { return repo.GetByNormalizedEmail(user.Email) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.get(); !errors.Is(err, domain.ErrUserNotFound) {
+				t.Fatalf("got error %v, want %v", err, domain.ErrUserNotFound)
+			}
+		})
+	}
+
+	page, total, err := repo.List(domain.UserFilter{Email: user.Email, Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 0 || len(page) != 0 {
+		t.Fatalf("List returned %d of %d users, want none", len(page), total)
+	}
+}
+
+func TestIncludingDeletedReadsReturnSoftDeletedUser(t *testing.T) {
+	repo := newTestUserRepository(t)
+	user := createTestUser(t, repo, testEmail())
+
+	if err := repo.SoftDelete(user.ID, user.ID); err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() (*domain.User, error)
+	}{
+		{"GetByIDIncludingDeleted", func() (*domain.User, error) { return repo.GetByIDIncludingDeleted(user.ID) }},
+		{"GetByEmailIncludingDeleted", func() (*domain.User, error) { return repo.GetByEmailIncludingDeleted(user.Email) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != user.ID || !got.IsDeleted || got.DeletedAt == nil {
+				t.Fatalf("got user %s deleted=%v deletedAt=%v, want deleted %s", got.ID, got.IsDeleted, got.DeletedAt, user.ID)
+			}
+			if got.DeletedBy != user.ID || got.StatusBeforeDelete != domain.UserStatusActive {
+				t.Fatalf("got deletedBy=%q statusBeforeDelete=%q", got.DeletedBy, got.StatusBeforeDelete)
+			}
+		})
+	}
+
+	page, total, err := repo.List(domain.UserFilter{Email: user.Email, IncludeDeleted: true, Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || len(page) != 1 || page[0].ID != user.ID {
+		t.Fatalf("List returned %d of %d users, want the deleted user", len(page), total)
+	}
+}
+
+func TestEmailCanBeRegisteredAgainAfterSoftDelete(t *testing.T) {
+	repo := newTestUserRepository(t)
+	email := testEmail()
+	original := createTestUser(t, repo, email)
+
+	now := time.Now()
+	duplicate := &domain.User{
+		ID:        uuid.New().String(),
+		Email:     email,
+		Name:      "Duplicate",
+		Password:  "hash",
+		Status:    domain.UserStatusInactive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repo.Create(duplicate); !errors.Is(err, domain.ErrUserAlreadyExists) {
+		t.Fatalf("Create with a live email: got %v, want %v", err, domain.ErrUserAlreadyExists)
+	}
+
+	if err := repo.SoftDelete(original.ID, original.ID); err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+
+	replacement := createTestUser(t, repo, email)
+
+	got, err := repo.GetByEmail(email)
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if got.ID != replacement.ID {
+		t.Fatalf("GetByEmail returned %s, want the new account %s", got.ID, replacement.ID)
+	}
+
+	// The deleted account cannot come back while its email is taken
+	if _, err := repo.Restore(original.ID); !errors.Is(err, domain.ErrUserAlreadyExists) {
+		t.Fatalf("Restore: got %v, want %v", err, domain.ErrUserAlreadyExists)
+	}
+}
+
+func TestRestoreBringsBackPriorStatus(t *testing.T) {
+	repo := newTestUserRepository(t)
+	user := createTestUser(t, repo, testEmail())
+
+	if err := repo.UpdateStatus(user.ID, domain.StatusChange{Status: domain.UserStatusDeactivated}); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := repo.SoftDelete(user.ID, ""); err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+
+	status, err := repo.Restore(user.ID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if status != domain.UserStatusDeactivated {
+		t.Fatalf("Restore returned status %q, want %q", status, domain.UserStatusDeactivated)
+	}
+
+	got, err := repo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID after restore: %v", err)
+	}
+	if got.IsDeleted || got.IsActive || got.Status != domain.UserStatusDeactivated {
+		t.Fatalf("got deleted=%v active=%v status=%q after restore", got.IsDeleted, got.IsActive, got.Status)
+	}
+}
+
+func TestNormalizeEmailsAppliesRulesAndReportsConflicts(t *testing.T) {
+	repo := newTestUserRepository(t)
+	suffix := uuid.New().String()
+	older := createTestUser(t, repo, "ada.lovelace-"+suffix+"@gmail.com")
+	newer := createTestUser(t, repo, "adalovelace-"+suffix+"@gmail.com")
+
+	gmailDots := emailaddress.Rules{GmailDots: true}
+	conflicts, err := repo.NormalizeEmails(gmailDots.Normalize)
+	if err != nil {
+		t.Fatalf("NormalizeEmails: %v", err)
+	}
+
+	var ours []domain.EmailConflict
+	for _, conflict := range conflicts {
+		if conflict.UserID == older.ID || conflict.UserID == newer.ID {
+			ours = append(ours, conflict)
+		}
+	}
+	canonical := "adalovelace-" + suffix + "@gmail.com"
+	if len(ours) != 1 || ours[0].UserID != newer.ID || ours[0].HeldBy != older.ID || ours[0].NormalizedEmail != canonical {
+		t.Fatalf("got conflicts %+v, want the newer account blocked by the older one", ours)
+	}
+
+	got, err := repo.GetByNormalizedEmail(canonical)
+	if err != nil {
+		t.Fatalf("GetByNormalizedEmail: %v", err)
+	}
+	if got.ID != older.ID {
+		t.Fatalf("canonical address belongs to %s, want the older account %s", got.ID, older.ID)
+	}
+
+	// Turning the rule off separates the accounts again
+	if _, err := repo.NormalizeEmails(emailaddress.Rules{}.Normalize); err != nil {
+		t.Fatalf("NormalizeEmails without Gmail rules: %v", err)
+	}
+	if got, err := repo.GetByNormalizedEmail(older.Email); err != nil || got.ID != older.ID {
+		t.Fatalf("GetByNormalizedEmail(%s): got %v, %v", older.Email, got, err)
+	}
+}
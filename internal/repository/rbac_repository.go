@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"auth-service/internal/cache"
+	"auth-service/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type cachedRoleRepository struct {
+	db    *sql.DB
+	cache cache.CacheService
+}
+
+func NewCachedRoleRepository(db *sql.DB, cache cache.CacheService) domain.RoleRepository {
+	return &cachedRoleRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+const (
+	userAccessCacheDuration = 5 * time.Minute
+	userRolesKey            = "user:roles:%s"
+	userPermissionsKey      = "user:permissions:%s"
+)
+
+func (r *cachedRoleRepository) CreateRole(role *domain.Role) error {
+	query := `
+        INSERT INTO roles (name, description)
+        VALUES ($1, $2)
+        RETURNING id, created_at, updated_at
+    `
+
+	err := r.db.QueryRow(query, role.Name, role.Description).Scan(&role.ID, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrRoleAlreadyExists
+		}
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cachedRoleRepository) GetRoleByName(name string) (*domain.Role, error) {
+	role := &domain.Role{}
+	query := `
+        SELECT id, name, description, created_at, updated_at
+        FROM roles
+        WHERE name = $1
+    `
+
+	err := r.db.QueryRow(query, name).Scan(
+		&role.ID,
+		&role.Name,
+		&role.Description,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	permissions, err := r.queryStrings(`
+        SELECT p.name
+        FROM permissions p
+        JOIN role_permissions rp ON rp.permission_id = p.id
+        WHERE rp.role_id = $1
+        ORDER BY p.name
+    `, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	role.Permissions = permissions
+
+	return role, nil
+}
+
+func (r *cachedRoleRepository) ListRoles() ([]*domain.Role, error) {
+	query := `
+        SELECT r.id, r.name, r.description, r.created_at, r.updated_at,
+               COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+        FROM roles r
+        LEFT JOIN role_permissions rp ON rp.role_id = r.id
+        LEFT JOIN permissions p ON p.id = rp.permission_id
+        GROUP BY r.id
+        ORDER BY r.name
+    `
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []*domain.Role{}
+	for rows.Next() {
+		role := &domain.Role{}
+		if err := rows.Scan(
+			&role.ID,
+			&role.Name,
+			&role.Description,
+			&role.CreatedAt,
+			&role.UpdatedAt,
+			pq.Array(&role.Permissions),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *cachedRoleRepository) CreatePermission(permission *domain.Permission) error {
+	query := `
+        INSERT INTO permissions (name, description)
+        VALUES ($1, $2)
+        RETURNING id, created_at
+    `
+
+	err := r.db.QueryRow(query, permission.Name, permission.Description).Scan(&permission.ID, &permission.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrPermissionAlreadyExists
+		}
+		return fmt.Errorf("failed to create permission: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cachedRoleRepository) ListPermissions() ([]*domain.Permission, error) {
+	rows, err := r.db.Query(`SELECT id, name, description, created_at FROM permissions ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := []*domain.Permission{}
+	for rows.Next() {
+		permission := &domain.Permission{}
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+func (r *cachedRoleRepository) AddPermissionToRole(roleName, permissionName string) error {
+	roleID, permissionID, err := r.resolveRolePermission(roleName, permissionName)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO role_permissions (role_id, permission_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `
+	if _, err := r.db.Exec(query, roleID, permissionID); err != nil {
+		return fmt.Errorf("failed to grant permission: %w", err)
+	}
+
+	return r.invalidateRoleMembers(roleID)
+}
+
+func (r *cachedRoleRepository) RemovePermissionFromRole(roleName, permissionName string) error {
+	roleID, permissionID, err := r.resolveRolePermission(roleName, permissionName)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`
+	if _, err := r.db.Exec(query, roleID, permissionID); err != nil {
+		return fmt.Errorf("failed to revoke permission: %w", err)
+	}
+
+	return r.invalidateRoleMembers(roleID)
+}
+
+func (r *cachedRoleRepository) AssignRole(userID, roleName string) error {
+	query := `
+        INSERT INTO user_roles (user_id, role_id)
+        SELECT $1, id FROM roles WHERE name = $2
+        ON CONFLICT DO NOTHING
+    `
+
+	result, err := r.db.Exec(query, userID, roleName)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := r.GetRoleByName(roleName); err != nil {
+			return err
+		}
+	}
+
+	r.invalidateUser(userID)
+	return nil
+}
+
+func (r *cachedRoleRepository) RevokeRole(userID, roleName string) error {
+	query := `
+        DELETE FROM user_roles
+        WHERE user_id = $1 AND role_id = (SELECT id FROM roles WHERE name = $2)
+    `
+
+	result, err := r.db.Exec(query, userID, roleName)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		// Tell a missing role apart from one the user does not hold
+		var exists bool
+		if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1)`, roleName).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check role: %w", err)
+		}
+		if !exists {
+			return domain.ErrRoleNotFound
+		}
+		return domain.ErrRoleNotAssigned
+	}
+
+	r.invalidateUser(userID)
+	return nil
+}
+
+func (r *cachedRoleRepository) GetUserRoles(userID string) ([]string, error) {
+	var roles []string
+	err := r.cache.GetOrSet(context.Background(), fmt.Sprintf(userRolesKey, userID), &roles, userAccessCacheDuration, func() (interface{}, error) {
+		return r.queryStrings(`
+            SELECT r.name
+            FROM roles r
+            JOIN user_roles ur ON ur.role_id = r.id
+            WHERE ur.user_id = $1
+            ORDER BY r.name
+        `, userID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *cachedRoleRepository) GetUserPermissions(userID string) ([]string, error) {
+	var permissions []string
+	err := r.cache.GetOrSet(context.Background(), fmt.Sprintf(userPermissionsKey, userID), &permissions, userAccessCacheDuration, func() (interface{}, error) {
+		return r.queryStrings(`
+            SELECT DISTINCT p.name
+            FROM permissions p
+            JOIN role_permissions rp ON rp.permission_id = p.id
+            JOIN user_roles ur ON ur.role_id = rp.role_id
+            WHERE ur.user_id = $1
+            ORDER BY p.name
+        `, userID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+func (r *cachedRoleRepository) resolveRolePermission(roleName, permissionName string) (string, string, error) {
+	var roleID string
+	if err := r.db.QueryRow(`SELECT id FROM roles WHERE name = $1`, roleName).Scan(&roleID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", domain.ErrRoleNotFound
+		}
+		return "", "", fmt.Errorf("failed to get role: %w", err)
+	}
+
+	var permissionID string
+	if err := r.db.QueryRow(`SELECT id FROM permissions WHERE name = $1`, permissionName).Scan(&permissionID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", domain.ErrPermissionNotFound
+		}
+		return "", "", fmt.Errorf("failed to get permission: %w", err)
+	}
+
+	return roleID, permissionID, nil
+}
+
+// invalidateRoleMembers drops cached access data for every user holding the role
+func (r *cachedRoleRepository) invalidateRoleMembers(roleID string) error {
+	userIDs, err := r.queryStrings(`SELECT user_id FROM user_roles WHERE role_id = $1`, roleID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		r.invalidateUser(userID)
+	}
+
+	return nil
+}
+
+func (r *cachedRoleRepository) invalidateUser(userID string) {
+	ctx := context.Background()
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userRolesKey, userID))
+	_ = r.cache.Delete(ctx, fmt.Sprintf(userPermissionsKey, userID))
+}
+
+func (r *cachedRoleRepository) queryStrings(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"auth-service/internal/domain"
 	"context"
 	"time"
 
@@ -22,3 +23,314 @@ func (r *RedisRepository) StoreOTP(ctx context.Context, email, otp string) error
 func (r *RedisRepository) GetOTP(ctx context.Context, email string) (string, error) {
 	return r.client.Get(ctx, "otp:"+email).Result()
 }
+
+// DeleteOTP consumes the user's OTP along with its failed attempt count
+func (r *RedisRepository) DeleteOTP(ctx context.Context, email string) error {
+	return r.client.Del(ctx, "otp:"+email, "otp:attempts:"+email).Err()
+}
+
+// IncrementOTPAttempts counts a wrong OTP and returns the number of failures
+// since the OTP was issued
+func (r *RedisRepository) IncrementOTPAttempts(ctx context.Context, email string) (int64, error) {
+	key := "otp:attempts:" + email
+	attempts, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if attempts == 1 {
+		r.client.Expire(ctx, key, 5*time.Minute)
+	}
+	return attempts, nil
+}
+
+// StoreStepUpOTP holds the code for a login challenged for step-up
+// verification. It is kept apart from the email verification OTP so the
+// public verification endpoint cannot be used to guess it.
+func (r *RedisRepository) StoreStepUpOTP(ctx context.Context, userID, otp string, ttl time.Duration) error {
+	return r.client.Set(ctx, "stepup:"+userID, otp, ttl).Err()
+}
+
+func (r *RedisRepository) GetStepUpOTP(ctx context.Context, userID string) (string, error) {
+	return r.client.Get(ctx, "stepup:"+userID).Result()
+}
+
+// DeleteStepUpOTP consumes the step-up code along with its failed attempt count
+func (r *RedisRepository) DeleteStepUpOTP(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, "stepup:"+userID, "stepup:attempts:"+userID).Err()
+}
+
+// IncrementStepUpAttempts counts a wrong step-up code and returns the number
+// of failures since the code was issued
+func (r *RedisRepository) IncrementStepUpAttempts(ctx context.Context, userID string, ttl time.Duration) (int64, error) {
+	key := "stepup:attempts:" + userID
+	attempts, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if attempts == 1 {
+		r.client.Expire(ctx, key, ttl)
+	}
+	return attempts, nil
+}
+
+// reservePhoneSendScript enforces the cooldown and daily cap for phone
+// verification codes, by user and by number, and records the send when both
+// allow it. It returns -1 during a cooldown and -2 at the daily cap.
+var reservePhoneSendScript = redis.NewScript(`
+local userCooldown, numberCooldown, userDaily, numberDaily = KEYS[1], KEYS[2], KEYS[3], KEYS[4]
+local cooldown, limit = tonumber(ARGV[1]), tonumber(ARGV[2])
+
+if redis.call("EXISTS", userCooldown, numberCooldown) > 0 then
+    return -1
+end
+if limit > 0 then
+    local sent = math.max(tonumber(redis.call("GET", userDaily) or "0"), tonumber(redis.call("GET", numberDaily) or "0"))
+    if sent >= limit then
+        return -2
+    end
+end
+
+if cooldown > 0 then
+    redis.call("SET", userCooldown, "1", "PX", cooldown)
+    redis.call("SET", numberCooldown, "1", "PX", cooldown)
+end
+for _, key in ipairs({userDaily, numberDaily}) do
+    if redis.call("INCR", key) == 1 then
+        redis.call("EXPIRE", key, 86400)
+    end
+end
+return 0
+`)
+
+// ReservePhoneVerificationSend claims a verification code send for the user
+// and number. It reports ErrVerificationCooldown or
+// ErrVerificationLimitReached when the code must not be sent. A daily limit
+// of zero means unlimited.
+func (r *RedisRepository) ReservePhoneVerificationSend(ctx context.Context, userID, phoneNumber string, cooldown time.Duration, dailyLimit int) error {
+	result, err := reservePhoneSendScript.Run(ctx, r.client, []string{
+		"phone:cooldown:" + userID,
+		"phone:cooldown:number:" + phoneNumber,
+		"phone:daily:" + userID,
+		"phone:daily:number:" + phoneNumber,
+	}, cooldown.Milliseconds(), dailyLimit).Int()
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case -1:
+		return domain.ErrVerificationCooldown
+	case -2:
+		return domain.ErrVerificationLimitReached
+	default:
+		return nil
+	}
+}
+
+// StorePhoneVerification holds the number being verified with its code,
+// replacing any verification already in progress for the user
+func (r *RedisRepository) StorePhoneVerification(ctx context.Context, userID, phoneNumber, code string) error {
+	key := "phone:verify:" + userID
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "phone", phoneNumber, "code", code)
+	pipe.Expire(ctx, key, 5*time.Minute)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRepository) GetPhoneVerification(ctx context.Context, userID string) (phoneNumber, code string, err error) {
+	values, err := r.client.HGetAll(ctx, "phone:verify:"+userID).Result()
+	if err != nil {
+		return "", "", err
+	}
+	if values["code"] == "" {
+		return "", "", redis.Nil
+	}
+	return values["phone"], values["code"], nil
+}
+
+// IncrementPhoneVerificationAttempts counts a wrong code and returns the
+// number of failures since it was sent
+func (r *RedisRepository) IncrementPhoneVerificationAttempts(ctx context.Context, userID string) (int64, error) {
+	return r.client.HIncrBy(ctx, "phone:verify:"+userID, "attempts", 1).Result()
+}
+
+func (r *RedisRepository) DeletePhoneVerification(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, "phone:verify:"+userID).Err()
+}
+
+// StoreEmailChangeCode holds the requested address with its confirmation
+// code, replacing any change already in progress for the user
+func (r *RedisRepository) StoreEmailChangeCode(ctx context.Context, userID, email, code string) error {
+	key := "email:change:" + userID
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "email", email, "code", code)
+	pipe.Expire(ctx, key, 5*time.Minute)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRepository) GetEmailChangeCode(ctx context.Context, userID string) (email, code string, err error) {
+	values, err := r.client.HGetAll(ctx, "email:change:"+userID).Result()
+	if err != nil {
+		return "", "", err
+	}
+	if values["code"] == "" {
+		return "", "", redis.Nil
+	}
+	return values["email"], values["code"], nil
+}
+
+// IncrementEmailChangeAttempts counts a wrong code and returns the number
+// of failures since it was sent
+func (r *RedisRepository) IncrementEmailChangeAttempts(ctx context.Context, userID string) (int64, error) {
+	return r.client.HIncrBy(ctx, "email:change:"+userID, "attempts", 1).Result()
+}
+
+func (r *RedisRepository) DeleteEmailChangeCode(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, "email:change:"+userID).Err()
+}
+
+func (r *RedisRepository) StorePasswordResetCode(ctx context.Context, email, code string) error {
+	return r.client.Set(ctx, "reset:"+email, code, 30*time.Minute).Err()
+}
+
+func (r *RedisRepository) GetPasswordResetCode(ctx context.Context, email string) (string, error) {
+	return r.client.Get(ctx, "reset:"+email).Result()
+}
+
+// DeletePasswordResetCode consumes the code along with its failed attempt count
+func (r *RedisRepository) DeletePasswordResetCode(ctx context.Context, email string) error {
+	return r.client.Del(ctx, "reset:"+email, "reset:attempts:"+email).Err()
+}
+
+// IncrementPasswordResetAttempts counts a wrong reset code and returns the
+// number of failures since the code was issued
+func (r *RedisRepository) IncrementPasswordResetAttempts(ctx context.Context, email string) (int64, error) {
+	key := "reset:attempts:" + email
+	attempts, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if attempts == 1 {
+		r.client.Expire(ctx, key, 30*time.Minute)
+	}
+	return attempts, nil
+}
+
+// DeleteEmailData removes the short-lived keys held under an email address
+func (r *RedisRepository) DeleteEmailData(ctx context.Context, email string) error {
+	return r.client.Del(ctx, "otp:"+email, "otp:attempts:"+email, "reset:"+email, "reset:attempts:"+email).Err()
+}
+
+// DeleteUserData removes every short-lived key held for the user, under
+// both their ID and their email
+func (r *RedisRepository) DeleteUserData(ctx context.Context, userID, email string) error {
+	if err := r.DeleteEmailData(ctx, email); err != nil {
+		return err
+	}
+	return r.client.Del(ctx, "email:change:"+userID, "phone:verify:"+userID, "stepup:"+userID, "stepup:attempts:"+userID, "sessions:active:"+userID).Err()
+}
+
+// ConsumeActionToken marks a single-use link as used. It reports false when
+// the link was used before.
+func (r *RedisRepository) ConsumeActionToken(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, "action:used:"+tokenID, "1", ttl).Result()
+}
+
+func (r *RedisRepository) IsActionTokenUsed(ctx context.Context, tokenID string) (bool, error) {
+	count, err := r.client.Exists(ctx, "action:used:"+tokenID).Result()
+	return count > 0, err
+}
+
+// acquireSessionScript registers a session in the user's active set after
+// pruning expired entries. At the limit it either refuses (returns -1) or,
+// when evicting, drops the oldest sessions and returns their families.
+var acquireSessionScript = redis.NewScript(`
+local key, now, cutoff, family = KEYS[1], ARGV[1], ARGV[2], ARGV[3]
+local limit, evict, ttl = tonumber(ARGV[4]), ARGV[5] == "1", tonumber(ARGV[6])
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", cutoff)
+
+local evicted = {}
+local count = redis.call("ZCARD", key)
+if limit > 0 and count >= limit then
+    if not evict then
+        return -1
+    end
+    local excess = count - limit + 1
+    evicted = redis.call("ZRANGE", key, 0, excess - 1)
+    redis.call("ZREMRANGEBYRANK", key, 0, excess - 1)
+end
+
+redis.call("ZADD", key, now, family)
+redis.call("EXPIRE", key, ttl)
+return evicted
+`)
+
+// AcquireSessionSlot atomically records a new session against the user's
+// concurrent login limit. A limit of zero means unlimited. It reports
+// ok=false when the limit is reached and evict is off.
+func (r *RedisRepository) AcquireSessionSlot(ctx context.Context, userID, family string, limit int, evict bool, lifetime time.Duration) (evicted []string, ok bool, err error) {
+	now := time.Now()
+	evictFlag := "0"
+	if evict {
+		evictFlag = "1"
+	}
+
+	result, err := acquireSessionScript.Run(ctx, r.client, []string{"sessions:active:" + userID},
+		now.UnixMilli(),
+		now.Add(-lifetime).UnixMilli(),
+		family,
+		limit,
+		evictFlag,
+		int(lifetime.Seconds()),
+	).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if code, isCode := result.(int64); isCode && code < 0 {
+		return nil, false, nil
+	}
+
+	items, _ := result.([]interface{})
+	for _, item := range items {
+		if value, isString := item.(string); isString {
+			evicted = append(evicted, value)
+		}
+	}
+
+	return evicted, true, nil
+}
+
+// RefreshSessionSlot moves a continued session's timestamp forward, so it is
+// neither pruned as expired nor evicted as the oldest. Sessions that no
+// longer hold a slot are left out.
+func (r *RedisRepository) RefreshSessionSlot(ctx context.Context, userID, family string, lifetime time.Duration) error {
+	key := "sessions:active:" + userID
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddXX(ctx, key, &redis.Z{Score: float64(time.Now().UnixMilli()), Member: family})
+		pipe.Expire(ctx, key, lifetime)
+		return nil
+	})
+	return err
+}
+
+// ReleaseSessionSlot frees the slot held by a session that ended early
+func (r *RedisRepository) ReleaseSessionSlot(ctx context.Context, userID, family string) error {
+	return r.client.ZRem(ctx, "sessions:active:"+userID, family).Err()
+}
+
+// MarkSessionEvicted remembers that a session lost its slot, so its tokens
+// can be rejected with a specific reason until they would have expired
+func (r *RedisRepository) MarkSessionEvicted(ctx context.Context, family string, ttl time.Duration) error {
+	return r.client.Set(ctx, "session:evicted:"+family, "1", ttl).Err()
+}
+
+func (r *RedisRepository) IsSessionEvicted(ctx context.Context, family string) (bool, error) {
+	count, err := r.client.Exists(ctx, "session:evicted:"+family).Result()
+	return count > 0, err
+}
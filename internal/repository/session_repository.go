@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"auth-service/internal/cache"
+	"auth-service/internal/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type cachedSessionRepository struct {
+	db    *sql.DB
+	cache cache.CacheService
+}
+
+func NewCachedSessionRepository(db *sql.DB, cache cache.CacheService) domain.SessionRepository {
+	return &cachedSessionRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+const (
+	sessionCacheDuration = 5 * time.Minute
+	sessionFamilyKey     = "session:family:%s"
+)
+
+const sessionColumns = `id, user_id, token_family, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at`
+
+func scanSession(row rowScanner) (*domain.Session, error) {
+	session := &domain.Session{}
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenFamily,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	return session, err
+}
+
+func (r *cachedSessionRepository) Create(session *domain.Session) error {
+	query := `
+        INSERT INTO sessions (user_id, token_family, user_agent, ip_address, expires_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at, last_seen_at
+    `
+
+	err := r.db.QueryRow(
+		query,
+		session.UserID,
+		session.TokenFamily,
+		session.UserAgent,
+		session.IPAddress,
+		session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByFamily is checked on every authenticated request, so it is cached
+func (r *cachedSessionRepository) GetByFamily(family string) (*domain.Session, error) {
+	var session domain.Session
+	err := r.cache.GetOrSet(context.Background(), fmt.Sprintf(sessionFamilyKey, family), &session, sessionCacheDuration, func() (interface{}, error) {
+		query := `SELECT ` + sessionColumns + ` FROM sessions WHERE token_family = $1`
+
+		s, err := scanSession(r.db.QueryRow(query, family))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, domain.ErrSessionNotFound
+			}
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.TokenFamily = family
+	return &session, nil
+}
+
+func (r *cachedSessionRepository) ListActiveByUser(userID string) ([]*domain.Session, error) {
+	query := `
+        SELECT ` + sessionColumns + `
+        FROM sessions
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+        ORDER BY last_seen_at DESC
+    `
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Extend pushes the expiry out when a new token is issued in the session
+func (r *cachedSessionRepository) Extend(family string, expiresAt time.Time) error {
+	query := `
+        UPDATE sessions
+        SET expires_at = GREATEST(expires_at, $2)
+        WHERE token_family = $1 AND revoked_at IS NULL
+    `
+
+	result, err := r.db.Exec(query, family, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to extend session: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrSessionRevoked
+	}
+
+	r.invalidate(family)
+	return nil
+}
+
+func (r *cachedSessionRepository) TouchLastSeen(family string) error {
+	query := `UPDATE sessions SET last_seen_at = CURRENT_TIMESTAMP WHERE token_family = $1`
+
+	if _, err := r.db.Exec(query, family); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	r.invalidate(family)
+	return nil
+}
+
+// Revoke ends one of the user's sessions and returns its token family
+func (r *cachedSessionRepository) Revoke(userID, id string) (string, error) {
+	query := `
+        UPDATE sessions
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+        RETURNING token_family
+    `
+
+	var family string
+	if err := r.db.QueryRow(query, id, userID).Scan(&family); err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrSessionNotFound
+		}
+		return "", fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	r.invalidate(family)
+	return family, nil
+}
+
+func (r *cachedSessionRepository) RevokeByFamily(family string) error {
+	query := `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE token_family = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.Exec(query, family); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	r.invalidate(family)
+	return nil
+}
+
+// RevokeAllByUser ends every active session of the user and returns their
+// token families
+func (r *cachedSessionRepository) RevokeAllByUser(userID string) ([]string, error) {
+	query := `
+        UPDATE sessions
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE user_id = $1 AND revoked_at IS NULL
+        RETURNING token_family
+    `
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var families []string
+	for rows.Next() {
+		var family string
+		if err := rows.Scan(&family); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		families = append(families, family)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, family := range families {
+		r.invalidate(family)
+	}
+	return families, nil
+}
+
+func (r *cachedSessionRepository) invalidate(family string) {
+	_ = r.cache.Delete(context.Background(), fmt.Sprintf(sessionFamilyKey, family))
+}
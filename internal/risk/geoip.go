@@ -0,0 +1,63 @@
+package risk
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location is where an IP address is registered
+type Location struct {
+	Country        string
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+}
+
+// GeoIP resolves addresses against an offline MaxMind database, such as
+// GeoLite2-City or GeoLite2-Country
+type GeoIP struct {
+	reader *maxminddb.Reader
+}
+
+type geoRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+func OpenGeoIP(path string) (*GeoIP, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+
+	return &GeoIP{reader: reader}, nil
+}
+
+// Lookup reports false for addresses the database does not cover, such as
+// private ranges
+func (g *GeoIP) Lookup(ip net.IP) (*Location, bool) {
+	var record geoRecord
+	if err := g.reader.Lookup(ip, &record); err != nil || record.Country.ISOCode == "" {
+		return nil, false
+	}
+
+	location := &Location{Country: record.Country.ISOCode}
+	if record.Location.Latitude != nil && record.Location.Longitude != nil {
+		location.Latitude = *record.Location.Latitude
+		location.Longitude = *record.Location.Longitude
+		location.HasCoordinates = true
+	}
+
+	return location, true
+}
+
+func (g *GeoIP) Close() error {
+	return g.reader.Close()
+}
@@ -0,0 +1,97 @@
+package risk
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReputationList scores addresses known for abuse, such as Tor exit nodes,
+// open proxies or hosting ranges. Each line of the file holds an address or
+// CIDR range and its score:
+//
+//	# Known credential stuffing sources
+//	203.0.113.0/24  80
+//	198.51.100.7    40
+type ReputationList struct {
+	entries []reputationEntry
+}
+
+type reputationEntry struct {
+	network *net.IPNet
+	score   int
+}
+
+func LoadReputationList(path string) (*ReputationList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open IP reputation list: %w", err)
+	}
+	defer file.Close()
+
+	list := &ReputationList{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected an address and a score", path, line)
+		}
+
+		network, err := parseNetwork(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		score, err := strconv.Atoi(fields[1])
+		if err != nil || score < 0 {
+			return nil, fmt.Errorf("%s:%d: invalid score %q", path, line, fields[1])
+		}
+
+		list.entries = append(list.entries, reputationEntry{network: network, score: score})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read IP reputation list: %w", err)
+	}
+
+	return list, nil
+}
+
+// Score returns the highest score of any entry covering the address
+func (l *ReputationList) Score(ip net.IP) int {
+	best := 0
+	for _, entry := range l.entries {
+		if entry.score > best && entry.network.Contains(ip) {
+			best = entry.score
+		}
+	}
+	return best
+}
+
+func parseNetwork(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", value)
+		}
+		return network, nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", value)
+	}
+	bits := 8 * net.IPv6len
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits = v4, 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
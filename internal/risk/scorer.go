@@ -0,0 +1,121 @@
+// Package risk scores login attempts by where they come from, so unusual
+// sign-ins can be challenged before a token is issued.
+package risk
+
+import (
+	"math"
+	"net"
+	"time"
+)
+
+// Reasons a login was considered risky
+const (
+	ReasonCountryChange    = "country_change"
+	ReasonImpossibleTravel = "impossible_travel"
+	ReasonIPReputation     = "ip_reputation"
+)
+
+const (
+	countryChangeScore    = 30
+	impossibleTravelScore = 60
+
+	// minTravelKm ignores short hops that are within GeoIP accuracy
+	minTravelKm   = 100
+	earthRadiusKm = 6371
+)
+
+// Attempt is a login at a point in time
+type Attempt struct {
+	IPAddress string
+	At        time.Time
+}
+
+// Assessment is the risk of a login. Higher scores are riskier.
+type Assessment struct {
+	Score   int
+	Reasons []string
+}
+
+// locator resolves addresses to locations. GeoIP implements it.
+type locator interface {
+	Lookup(ip net.IP) (*Location, bool)
+}
+
+// Scorer compares a login with the user's previous successful one. Either
+// data source may be nil.
+type Scorer struct {
+	geo         locator
+	reputation  *ReputationList
+	maxSpeedKmh float64
+}
+
+func NewScorer(geo *GeoIP, reputation *ReputationList, maxSpeedKmh float64) *Scorer {
+	scorer := &Scorer{
+		reputation:  reputation,
+		maxSpeedKmh: maxSpeedKmh,
+	}
+	// Keep a nil database a nil interface, so lookups are skipped
+	if geo != nil {
+		scorer.geo = geo
+	}
+	return scorer
+}
+
+// Score assesses the current attempt. previous is nil on a first login.
+func (s *Scorer) Score(previous *Attempt, current Attempt) Assessment {
+	var assessment Assessment
+
+	ip := net.ParseIP(current.IPAddress)
+	if ip == nil {
+		return assessment
+	}
+
+	if s.reputation != nil {
+		if score := s.reputation.Score(ip); score > 0 {
+			assessment.add(ReasonIPReputation, score)
+		}
+	}
+
+	if s.geo == nil || previous == nil {
+		return assessment
+	}
+
+	from, ok := s.geo.Lookup(net.ParseIP(previous.IPAddress))
+	if !ok {
+		return assessment
+	}
+	to, ok := s.geo.Lookup(ip)
+	if !ok {
+		return assessment
+	}
+
+	if from.Country != to.Country {
+		assessment.add(ReasonCountryChange, countryChangeScore)
+	}
+
+	if from.HasCoordinates && to.HasCoordinates {
+		distance := distanceKm(from, to)
+		hours := current.At.Sub(previous.At).Hours()
+		if distance >= minTravelKm && (hours <= 0 || distance/hours > s.maxSpeedKmh) {
+			assessment.add(ReasonImpossibleTravel, impossibleTravelScore)
+		}
+	}
+
+	return assessment
+}
+
+func (a *Assessment) add(reason string, score int) {
+	a.Score += score
+	a.Reasons = append(a.Reasons, reason)
+}
+
+// distanceKm is the great-circle distance between two locations
+func distanceKm(from, to *Location) float64 {
+	lat1 := from.Latitude * math.Pi / 180
+	lat2 := to.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (to.Longitude - from.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,125 @@
+package risk
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// staticLocator places addresses from a fixed table
+type staticLocator map[string]*Location
+
+func (l staticLocator) Lookup(ip net.IP) (*Location, bool) {
+	location, ok := l[ip.String()]
+	return location, ok
+}
+
+var testLocations = staticLocator{
+	"192.0.2.1":   {Country: "DE", Latitude: 52.52, Longitude: 13.405, HasCoordinates: true},   // Berlin
+	"192.0.2.2":   {Country: "DE", Latitude: 52.52, Longitude: 13.405, HasCoordinates: true},   // Berlin
+	"192.0.2.3":   {Country: "DE", Latitude: 53.551, Longitude: 9.994, HasCoordinates: true},   // Hamburg, ~255 km away
+	"192.0.2.4":   {Country: "PL", Latitude: 52.35, Longitude: 14.55, HasCoordinates: true},    // Słubice, ~80 km away
+	"192.0.2.5":   {Country: "US", Latitude: 40.713, Longitude: -74.006, HasCoordinates: true}, // New York, ~6,400 km away
+	"192.0.2.6":   {Country: "FR"},
+	"203.0.113.9": {Country: "DE", Latitude: 52.52, Longitude: 13.405, HasCoordinates: true},
+}
+
+func writeReputationList(t *testing.T, contents string) *ReputationList {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "reputation.txt")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write reputation list: %v", err)
+	}
+	list, err := LoadReputationList(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return list
+}
+
+func TestScore(t *testing.T) {
+	reputation := writeReputationList(t, `
+# Known credential stuffing sources
+203.0.113.0/24  80
+203.0.113.9     20
+198.51.100.7    40
+2001:db8::/32   50
+`)
+	scorer := &Scorer{geo: testLocations, reputation: reputation, maxSpeedKmh: 900}
+
+	now := time.Now()
+	from := func(ip string, ago time.Duration) *Attempt {
+		return &Attempt{IPAddress: ip, At: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name        string
+		previous    *Attempt
+		current     string
+		wantScore   int
+		wantReasons []string
+	}{
+		{"first login", nil, "192.0.2.1", 0, nil},
+		{"same place", from("192.0.2.1", time.Minute), "192.0.2.2", 0, nil},
+		{"unparseable address", from("192.0.2.1", time.Minute), "not-an-ip", 0, nil},
+		{"unknown previous location", from("10.0.0.1", time.Minute), "192.0.2.5", 0, nil},
+		{"unknown current location", from("192.0.2.1", time.Minute), "10.0.0.1", 0, nil},
+		{"reachable in time", from("192.0.2.1", 2*time.Hour), "192.0.2.3", 0, nil},
+		{"impossible travel", from("192.0.2.1", 10*time.Minute), "192.0.2.3", impossibleTravelScore, []string{ReasonImpossibleTravel}},
+		{"simultaneous logins far apart", from("192.0.2.1", 0), "192.0.2.3", impossibleTravelScore, []string{ReasonImpossibleTravel}},
+		{"short hop under the travel cutoff", from("192.0.2.1", time.Second), "192.0.2.4", countryChangeScore, []string{ReasonCountryChange}},
+		{"country change reachable in time", from("192.0.2.1", 12*time.Hour), "192.0.2.5", countryChangeScore, []string{ReasonCountryChange}},
+		{
+			"country change and impossible travel",
+			from("192.0.2.1", time.Hour), "192.0.2.5",
+			countryChangeScore + impossibleTravelScore,
+			[]string{ReasonCountryChange, ReasonImpossibleTravel},
+		},
+		{"country change without coordinates", from("192.0.2.1", time.Minute), "192.0.2.6", countryChangeScore, []string{ReasonCountryChange}},
+		{"reputation CIDR match", nil, "203.0.113.200", 80, []string{ReasonIPReputation}},
+		{"highest matching entry wins", nil, "203.0.113.9", 80, []string{ReasonIPReputation}},
+		{"reputation single address", nil, "198.51.100.7", 40, []string{ReasonIPReputation}},
+		{"outside single address", nil, "198.51.100.8", 0, nil},
+		{"reputation IPv6 range", nil, "2001:db8::1", 50, []string{ReasonIPReputation}},
+		{
+			"reputation adds to location reasons",
+			from("192.0.2.5", time.Minute), "203.0.113.9",
+			80 + countryChangeScore + impossibleTravelScore,
+			[]string{ReasonIPReputation, ReasonCountryChange, ReasonImpossibleTravel},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scorer.Score(tt.previous, Attempt{IPAddress: tt.current, At: now})
+			if got.Score != tt.wantScore || !reflect.DeepEqual(got.Reasons, tt.wantReasons) {
+				t.Fatalf("got score %d reasons %v, want score %d reasons %v", got.Score, got.Reasons, tt.wantScore, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestScoreWithoutDataSources(t *testing.T) {
+	scorer := NewScorer(nil, nil, 900)
+
+	got := scorer.Score(&Attempt{IPAddress: "192.0.2.1", At: time.Now()}, Attempt{IPAddress: "192.0.2.5", At: time.Now()})
+	if got.Score != 0 || got.Reasons != nil {
+		t.Fatalf("got score %d reasons %v, want no risk", got.Score, got.Reasons)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	berlin := testLocations["192.0.2.1"]
+	hamburg := testLocations["192.0.2.3"]
+
+	if d := distanceKm(berlin, hamburg); d < 250 || d > 260 {
+		t.Fatalf("got %.1f km from Berlin to Hamburg, want about 255", d)
+	}
+	if d := distanceKm(berlin, berlin); d != 0 {
+		t.Fatalf("got %.1f km for the same place, want 0", d)
+	}
+}
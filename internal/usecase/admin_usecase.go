@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type adminUsecase struct {
+	userRepo    domain.UserRepository
+	redisRepo   *repository.RedisRepository
+	mailer      *email.Mailer
+	tokenIssuer *TokenIssuer
+}
+
+func NewAdminUsecase(
+	userRepo domain.UserRepository,
+	redisRepo *repository.RedisRepository,
+	mailer *email.Mailer,
+	tokenIssuer *TokenIssuer,
+) domain.AdminUsecase {
+	return &adminUsecase{
+		userRepo:    userRepo,
+		redisRepo:   redisRepo,
+		mailer:      mailer,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+func (u *adminUsecase) ListUsers(filter domain.UserFilter) (*domain.UserPage, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, domain.ErrInvalidUserStatus
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultPageSize
+	}
+	if filter.PageSize > maxPageSize {
+		filter.PageSize = maxPageSize
+	}
+
+	users, total, err := u.userRepo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserPage{
+		Users:    users,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+func (u *adminUsecase) GetUser(id string, includeDeleted bool) (*domain.User, error) {
+	if includeDeleted {
+		return u.userRepo.GetByIDIncludingDeleted(id)
+	}
+
+	return u.userRepo.GetByID(id)
+}
+
+func (u *adminUsecase) ActivateUser(id string) error {
+	return u.changeStatus(id, domain.StatusChange{Status: domain.UserStatusActive})
+}
+
+func (u *adminUsecase) DeactivateUser(id string) error {
+	return u.changeStatus(id, domain.StatusChange{Status: domain.UserStatusDeactivated})
+}
+
+func (u *adminUsecase) SuspendUser(id string, reason string, until *time.Time) error {
+	if until != nil && !until.After(time.Now()) {
+		return domain.NewValidationError("until", "suspension expiry must be in the future")
+	}
+
+	return u.changeStatus(id, domain.StatusChange{
+		Status: domain.UserStatusSuspended,
+		Reason: reason,
+		Until:  until,
+	})
+}
+
+// changeStatus applies a transition allowed by the user status state machine.
+// Re-applying a suspension is allowed so its reason or expiry can be updated.
+func (u *adminUsecase) changeStatus(id string, change domain.StatusChange) error {
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	current := user.EffectiveStatus(time.Now())
+	if current != change.Status && !current.CanTransitionTo(change.Status) {
+		return domain.ErrInvalidTransition
+	}
+	if current == change.Status && change.Status != domain.UserStatusSuspended {
+		return nil
+	}
+
+	return u.userRepo.UpdateStatus(id, change)
+}
+
+// ForcePasswordReset signs the user out everywhere and blocks logins until
+// they set a new password with the code emailed to them.
+func (u *adminUsecase) ForcePasswordReset(id string) error {
+	user, err := u.userRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.userRepo.SetPasswordResetRequired(id, true); err != nil {
+		return err
+	}
+
+	if err := u.tokenIssuer.EndAllSessions(id); err != nil {
+		return err
+	}
+
+	code := utils.GenerateOTP()
+	if err := u.redisRepo.StorePasswordResetCode(context.Background(), user.Email, code); err != nil {
+		return err
+	}
+
+	return u.mailer.SendTemplate(context.Background(), user.Email, user.Locale, email.TemplateReset, map[string]interface{}{
+		"Code":             code,
+		"ExpiresInMinutes": resetCodeLifetimeMinutes,
+	})
+}
+
+// DeleteUser soft deletes the account on the administrator's behalf, so the
+// user cannot undo it by signing in, or purges it when permanent is set
+func (u *adminUsecase) DeleteUser(actorID, id string, permanent bool) error {
+	if permanent {
+		// Soft-deleted accounts can be purged ahead of the grace period
+		user, err := u.userRepo.GetByIDIncludingDeleted(id)
+		if err != nil {
+			return err
+		}
+		return purgeUser(u.userRepo, u.redisRepo, user)
+	}
+
+	return u.userRepo.SoftDelete(id, actorID)
+}
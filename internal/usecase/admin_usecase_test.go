@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"errors"
+	"testing"
+)
+
+// deletedUserRepository holds a single soft-deleted user. Methods the tests
+// don't stub panic through the nil embedded interface.
+type deletedUserRepository struct {
+	domain.UserRepository
+	user       *domain.User
+	lastFilter domain.UserFilter
+}
+
+func (r *deletedUserRepository) GetByID(id string) (*domain.User, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *deletedUserRepository) GetByIDIncludingDeleted(id string) (*domain.User, error) {
+	if id != r.user.ID {
+		return nil, domain.ErrUserNotFound
+	}
+	return r.user, nil
+}
+
+func (r *deletedUserRepository) List(filter domain.UserFilter) ([]*domain.User, int, error) {
+	r.lastFilter = filter
+	if !filter.IncludeDeleted {
+		return []*domain.User{}, 0, nil
+	}
+	return []*domain.User{r.user}, 1, nil
+}
+
+func newDeletedUserRepository() *deletedUserRepository {
+	return &deletedUserRepository{
+		user: &domain.User{
+			ID:        "0b0f3a39-5a47-4d7e-9d0c-7c1f1f3f5a11",
+			Email:     "deleted@example.com",
+			Status:    domain.UserStatusDeleted,
+			IsDeleted: true,
+		},
+	}
+}
+
+func TestAdminGetUserIncludeDeleted(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeDeleted bool
+		wantErr        error
+	}{
+		{"hidden by default", false, domain.ErrUserNotFound},
+		{"returned when requested", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newDeletedUserRepository()
+			admin := NewAdminUsecase(repo, nil, nil, nil)
+
+			user, err := admin.GetUser(repo.user.ID, tt.includeDeleted)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (user == nil || !user.IsDeleted) {
+				t.Fatalf("got user %+v, want the deleted user", user)
+			}
+		})
+	}
+}
+
+func TestAdminListUsersIncludeDeleted(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeDeleted bool
+		wantTotal      int
+	}{
+		{"hidden by default", false, 0},
+		{"listed when requested", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newDeletedUserRepository()
+			admin := NewAdminUsecase(repo, nil, nil, nil)
+
+			page, err := admin.ListUsers(domain.UserFilter{IncludeDeleted: tt.includeDeleted})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.lastFilter.IncludeDeleted != tt.includeDeleted {
+				t.Fatalf("repository got IncludeDeleted=%v, want %v", repo.lastFilter.IncludeDeleted, tt.includeDeleted)
+			}
+			if page.Total != tt.wantTotal || len(page.Users) != tt.wantTotal {
+				t.Fatalf("got %d of %d users, want %d", len(page.Users), page.Total, tt.wantTotal)
+			}
+		})
+	}
+}
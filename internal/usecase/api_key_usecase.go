@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain"
+	"auth-service/internal/utils"
+	"errors"
+	"strings"
+	"time"
+)
+
+type apiKeyUsecase struct {
+	apiKeyRepo domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	roleRepo   domain.RoleRepository
+	groupRepo  domain.GroupRepository
+	config     *config.Config
+}
+
+func NewAPIKeyUsecase(
+	apiKeyRepo domain.APIKeyRepository,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	groupRepo domain.GroupRepository,
+	cfg *config.Config,
+) domain.APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		groupRepo:  groupRepo,
+		config:     cfg,
+	}
+}
+
+// CreateAPIKey stores the key and returns its secret, which is never
+// retrievable again. Scopes must be permissions the user currently holds.
+func (u *apiKeyUsecase) CreateAPIKey(userID string, key *domain.APIKey) (string, error) {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return "", domain.NewValidationError("name", "API key name is required")
+	}
+
+	if key.Kind == "" {
+		key.Kind = domain.APIKeyPersonal
+	}
+	if !key.Kind.IsValid() {
+		return "", domain.NewValidationError("kind", "kind must be personal or service")
+	}
+
+	if key.Kind == domain.APIKeyService {
+		roles, err := u.roleRepo.GetUserRoles(userID)
+		if err != nil {
+			return "", err
+		}
+		if !containsString(roles, domain.RoleAdmin) {
+			return "", domain.ErrForbidden
+		}
+	}
+
+	if err := u.validateScopes(userID, key.Scopes); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if key.ExpiresAt == nil && key.Kind == domain.APIKeyPersonal {
+		expiresAt := now.Add(u.config.APIKeys.DefaultExpiration)
+		key.ExpiresAt = &expiresAt
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return "", domain.NewValidationError("expiresAt", "expiry must be in the future")
+	}
+
+	secret, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	key.UserID = userID
+	key.Prefix = prefix
+	key.KeyHash = hash
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+
+	if err := u.apiKeyRepo.Create(key); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (u *apiKeyUsecase) ListAPIKeys(userID string) ([]*domain.APIKey, error) {
+	return u.apiKeyRepo.ListByUser(userID)
+}
+
+func (u *apiKeyUsecase) DeleteAPIKey(userID, id string) error {
+	return u.apiKeyRepo.Delete(userID, id)
+}
+
+// Authenticate resolves a presented key to its owner. The key's effective
+// permissions are its scopes intersected with what the owner holds now, so
+// revoking a permission from the user also narrows their keys.
+func (u *apiKeyUsecase) Authenticate(rawKey string) (*domain.APIKeyIdentity, error) {
+	prefix, ok := utils.ParseAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := u.apiKeyRepo.GetByPrefix(prefix)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !utils.CheckAPIKey(rawKey, key.KeyHash) || key.IsExpired(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := u.userRepo.GetByID(key.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if err := user.CheckStatus(now); err != nil {
+		return nil, err
+	}
+
+	granted, err := u.roleRepo.GetUserPermissions(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := u.groupRepo.GetUserGroups(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := []string{}
+	for _, scope := range key.Scopes {
+		if containsString(granted, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	// Usage tracking is best effort and never blocks the request
+	_ = u.apiKeyRepo.TouchLastUsed(key.ID)
+
+	return &domain.APIKeyIdentity{
+		Key:         key,
+		User:        user,
+		Permissions: permissions,
+		Groups:      groups,
+	}, nil
+}
+
+func (u *apiKeyUsecase) validateScopes(userID string, scopes []string) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	granted, err := u.roleRepo.GetUserPermissions(userID)
+	if err != nil {
+		return err
+	}
+
+	for i, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !isValidPermissionName(scope) {
+			return domain.NewValidationError("scopes", "scopes must look like resource:action")
+		}
+		if !containsString(granted, scope) {
+			return domain.NewValidationError("scopes", "cannot grant a scope you do not hold: "+scope)
+		}
+		scopes[i] = scope
+	}
+
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -1,39 +1,94 @@
 package usecase
 
 import (
+	"auth-service/internal/config"
 	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"auth-service/internal/emailaddress"
 	"auth-service/internal/repository"
 	"auth-service/internal/utils"
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Lifetimes of emailed codes, matching their Redis TTLs
+const (
+	otpLifetimeMinutes       = 5
+	resetCodeLifetimeMinutes = 30
+)
+
 type authUsecase struct {
-	userRepo     domain.UserRepository
-	redisRepo    *repository.RedisRepository
-	emailService utils.EmailService
+	userRepo    domain.UserRepository
+	roleRepo    domain.RoleRepository
+	redisRepo   *repository.RedisRepository
+	mailer      *email.Mailer
+	otpNotifier *OTPNotifier
+	tokenIssuer *TokenIssuer
+	recorder    *LoginRecorder
+	emailPolicy *emailaddress.Policy
+	config      *config.Config
 }
 
 func NewAuthUsecase(
 	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
 	redisRepo *repository.RedisRepository,
-	emailService utils.EmailService,
+	mailer *email.Mailer,
+	otpNotifier *OTPNotifier,
+	tokenIssuer *TokenIssuer,
+	recorder *LoginRecorder,
+	emailPolicy *emailaddress.Policy,
+	cfg *config.Config,
 ) domain.UserUsecase {
 	return &authUsecase{
-		userRepo:     userRepo,
-		redisRepo:    redisRepo,
-		emailService: emailService,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		redisRepo:   redisRepo,
+		mailer:      mailer,
+		otpNotifier: otpNotifier,
+		tokenIssuer: tokenIssuer,
+		recorder:    recorder,
+		emailPolicy: emailPolicy,
+		config:      cfg,
 	}
 }
 
 func (u *authUsecase) Register(user *domain.User) error {
-	// Check if user exists
-	existingUser, _ := u.userRepo.GetByEmail(user.Email)
-	if existingUser != nil {
-		return errors.New("email already registered")
+	if u.config.Registration.InviteOnly {
+		return domain.ErrRegistrationClosed
+	}
+
+	user.Email = strings.TrimSpace(user.Email)
+	if err := u.emailPolicy.Check(user.Email); err != nil {
+		return err
+	}
+
+	// Any variant of a registered address, such as a different case,
+	// belongs to the existing account
+	user.NormalizedEmail = u.emailPolicy.Normalize(user.Email)
+	if _, err := u.userRepo.GetByNormalizedEmail(user.NormalizedEmail); err == nil {
+		return domain.ErrUserAlreadyExists
+	} else if !errors.Is(err, domain.ErrUserNotFound) {
+		return err
+	}
+
+	if user.Username != "" {
+		username, err := domain.NormalizeUsername(user.Username)
+		if err != nil {
+			return err
+		}
+		if _, err := u.userRepo.GetByUsername(username); err == nil {
+			return domain.ErrUsernameTaken
+		} else if !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		user.Username = username
 	}
 
 	// Hash password
@@ -46,6 +101,8 @@ func (u *authUsecase) Register(user *domain.User) error {
 	user.ID = uuid.New().String()
 	user.Password = hashedPassword
 	user.IsActive = false
+	user.Status = domain.UserStatusInactive
+	user.EmailVerifiedAt = nil
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
@@ -53,65 +110,546 @@ func (u *authUsecase) Register(user *domain.User) error {
 		return err
 	}
 
-	// Generate and send OTP
+	if err := u.roleRepo.AssignRole(user.ID, domain.RoleUser); err != nil {
+		return err
+	}
+
+	return u.sendVerification(user)
+}
+
+// sendVerification emails a new user a code, a link or both, as configured
+func (u *authUsecase) sendVerification(user *domain.User) error {
+	mode := u.config.Registration.VerificationMode
+	data := map[string]interface{}{}
+
+	if mode != config.EmailVerificationLink {
+		otp := utils.GenerateOTP()
+		if err := u.redisRepo.StoreOTP(context.Background(), user.Email, otp); err != nil {
+			return err
+		}
+		data["OTP"] = otp
+		data["ExpiresInMinutes"] = otpLifetimeMinutes
+	}
+
+	if mode != config.EmailVerificationCode {
+		ttl := u.config.Registration.VerificationLinkTTL
+		token, err := utils.GenerateActionToken(utils.PurposeVerifyEmail, user.ID, ttl)
+		if err != nil {
+			return err
+		}
+		data["VerifyLink"] = u.config.App.BaseURL + "/api/auth/verify-email?token=" + url.QueryEscape(token)
+		data["LinkExpiresInHours"] = int(ttl.Hours())
+	}
+
+	return u.mailer.SendTemplate(context.Background(), user.Email, user.Locale, email.TemplateVerifyEmail, data)
+}
+
+// Login signs the user in and records the attempt in the login history
+func (u *authUsecase) Login(identifier, password string, client domain.ClientInfo) (string, error) {
+	user, token, err := u.login(strings.TrimSpace(identifier), password, client)
+	if err != nil {
+		u.recorder.RecordFailure(user, identifier, domain.LoginMethodPassword, client, err)
+		return "", err
+	}
+
+	u.recorder.RecordSuccess(user, domain.LoginMethodPassword, client)
+	return token, nil
+}
+
+// login returns the matched user, if any, alongside the outcome so failed
+// attempts can be attributed to the account
+func (u *authUsecase) login(identifier, password string, client domain.ClientInfo) (*domain.User, string, error) {
+	var user *domain.User
+	var err error
+	if strings.Contains(identifier, "@") {
+		user, err = findUserByEmail(u.userRepo, u.emailPolicy, identifier)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			// The account may be soft-deleted and still restorable
+			user, err = findUserByEmailIncludingDeleted(u.userRepo, u.emailPolicy, identifier)
+		}
+	} else {
+		// Usernames are released on deletion, so deleted accounts can only
+		// be restored by signing in with their email
+		user, err = u.userRepo.GetByUsername(identifier)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, "", domain.ErrInvalidCredentials
+		}
+		return nil, "", err
+	}
+
+	hashedPassword, err := u.userRepo.GetPasswordHash(user.ID)
+	if err != nil {
+		return user, "", err
+	}
+
+	if !utils.CheckPassword(password, hashedPassword) {
+		return user, "", domain.ErrInvalidCredentials
+	}
+
+	if err := u.admit(user); err != nil {
+		return user, "", err
+	}
+
+	if user.PasswordResetRequired {
+		return user, "", domain.ErrPasswordResetRequired
+	}
+
+	if u.config.Risk.Enabled {
+		if assessment := u.recorder.Assess(user, client); assessment.Score >= u.config.Risk.StepUpThreshold {
+			return user, "", u.requireStepUp(user, emailLocale(user, client))
+		}
+	}
+
+	// Signing in during the deletion grace period restores the account,
+	// once every other check has passed
+	if user.IsDeleted {
+		if err := u.restore(user); err != nil {
+			return user, "", err
+		}
+	}
+
+	token, err := u.tokenIssuer.StartSession(user, nil, client)
+	return user, token, err
+}
+
+// admit rejects users who may not sign in. A soft-deleted account is
+// judged by the status it had before deletion, and only its owner's own
+// deletion can be undone by signing in.
+func (u *authUsecase) admit(user *domain.User) error {
+	if !user.IsDeleted {
+		return u.enforceStatus(user)
+	}
+
+	if user.DeletedBy != user.ID {
+		return domain.ErrInvalidCredentials
+	}
+	if user.DeletedAt == nil || time.Since(*user.DeletedAt) > u.config.Account.DeletionGracePeriod {
+		return domain.ErrRestoreExpired
+	}
+
+	previous := *user
+	previous.IsDeleted = false
+	previous.Status = user.StatusBeforeDelete
+	err := previous.CheckStatus(time.Now())
+	if errors.Is(err, domain.ErrSuspendedUser) && user.SuspensionReason != "" {
+		return fmt.Errorf("%w: %s", err, user.SuspensionReason)
+	}
+	return err
+}
+
+// requireStepUp sends an OTP, to the user's verified phone when login codes
+// go over a phone channel and by email otherwise, and returns the challenge
+// the client submits it with
+func (u *authUsecase) requireStepUp(user *domain.User, locale string) error {
+	ttl := time.Duration(u.config.OTP.ExpirationMinutes) * time.Minute
+	challenge, err := utils.GenerateActionToken(utils.PurposeLoginStepUp, user.ID, ttl)
+	if err != nil {
+		return err
+	}
+
+	// Start the new code with a clean attempt count
+	ctx := context.Background()
+	if err := u.redisRepo.DeleteStepUpOTP(ctx, user.ID); err != nil {
+		return err
+	}
 	otp := utils.GenerateOTP()
-	err = u.redisRepo.StoreOTP(context.Background(), user.Email, otp)
+	if err := u.redisRepo.StoreStepUpOTP(ctx, user.ID, otp, ttl); err != nil {
+		return err
+	}
+
+	channel := u.config.Notify.OTPChannel
+	if channel != "email" && user.PhoneVerifiedAt != nil && u.otpNotifier.Available() {
+		err = u.otpNotifier.Send(channel, user.PhoneNumber, locale, otp)
+	} else {
+		channel = "email"
+		err = u.sendOTP(user.Email, locale, otp)
+	}
 	if err != nil {
 		return err
 	}
 
-	return u.emailService.SendOTP(user.Email, otp)
+	return &domain.StepUpRequiredError{Challenge: challenge, Channel: channel}
 }
 
-func (u *authUsecase) Login(email, password string) (string, error) {
-	user, err := u.userRepo.GetByEmail(email)
+// VerifyLoginOTP completes a login that was challenged for step-up
+// verification
+func (u *authUsecase) VerifyLoginOTP(challenge, otp string, client domain.ClientInfo) (string, error) {
+	userID, err := utils.ParseActionToken(challenge, utils.PurposeLoginStepUp)
 	if err != nil {
-		return "", domain.ErrInactiveUser
+		return "", err
 	}
 
-	if !user.IsActive {
-		return "", domain.ErrInvalidCredentials
+	// The login being completed may be restoring a deleted account
+	user, err := u.userRepo.GetByIDIncludingDeleted(userID)
+	if err != nil {
+		return "", err
 	}
 
-	// Generate JWT token
-	return utils.GenerateJWT(user)
+	token, err := u.verifyLoginOTP(user, otp, client)
+	if err != nil {
+		u.recorder.RecordFailure(user, user.Email, domain.LoginMethodOTPStepUp, client, err)
+		return "", err
+	}
+
+	u.recorder.RecordSuccess(user, domain.LoginMethodOTPStepUp, client)
+	return token, nil
 }
 
+func (u *authUsecase) verifyLoginOTP(user *domain.User, otp string, client domain.ClientInfo) (string, error) {
+	ctx := context.Background()
+
+	storedOTP, err := u.redisRepo.GetStepUpOTP(ctx, user.ID)
+	if err != nil {
+		return "", domain.ErrInvalidOTP
+	}
+
+	if storedOTP != otp {
+		ttl := time.Duration(u.config.OTP.ExpirationMinutes) * time.Minute
+		attempts, err := u.redisRepo.IncrementStepUpAttempts(ctx, user.ID, ttl)
+		if err != nil {
+			return "", err
+		}
+		if attempts >= int64(u.config.OTP.MaxAttempts) {
+			_ = u.redisRepo.DeleteStepUpOTP(ctx, user.ID)
+			return "", domain.ErrTooManyOTPAttempts
+		}
+		return "", domain.ErrInvalidOTP
+	}
+
+	if err := u.redisRepo.DeleteStepUpOTP(ctx, user.ID); err != nil {
+		return "", err
+	}
+
+	// The account may have changed since the password was checked
+	if err := u.admit(user); err != nil {
+		return "", err
+	}
+
+	if user.PasswordResetRequired {
+		return "", domain.ErrPasswordResetRequired
+	}
+
+	if user.IsDeleted {
+		if err := u.restore(user); err != nil {
+			return "", err
+		}
+	}
+
+	return u.tokenIssuer.StartSession(user, nil, client)
+}
+
+// VerifyOTP activates an account with the code from its verification email.
+// Wrong codes count towards the attempt limit, and a wrong code, an unknown
+// email and an already verified account all fail the same way, so the
+// endpoint reveals nothing about the account.
 func (u *authUsecase) VerifyOTP(email, otp string) error {
-	storedOTP, err := u.redisRepo.GetOTP(context.Background(), email)
+	ctx := context.Background()
+
+	storedOTP, err := u.redisRepo.GetOTP(ctx, email)
 	if err != nil {
-		return errors.New("invalid or expired OTP")
+		return domain.ErrInvalidOTP
 	}
 
 	if storedOTP != otp {
-		return errors.New("invalid OTP")
+		attempts, err := u.redisRepo.IncrementOTPAttempts(ctx, email)
+		if err != nil {
+			return err
+		}
+		if attempts >= int64(u.config.OTP.MaxAttempts) {
+			_ = u.redisRepo.DeleteOTP(ctx, email)
+			return domain.ErrTooManyOTPAttempts
+		}
+		return domain.ErrInvalidOTP
 	}
 
 	user, err := u.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidOTP
+		}
+		return err
+	}
+
+	if err := u.activate(user); err != nil {
+		if errors.Is(err, domain.ErrOTPAlreadyVerified) {
+			return domain.ErrInvalidOTP
+		}
+		return err
+	}
+
+	return nil
+}
+
+// VerifyEmail activates the account named by an emailed verification link
+func (u *authUsecase) VerifyEmail(token string) error {
+	userID, err := utils.ParseActionToken(token, utils.PurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return u.activate(user)
+}
+
+// activate completes email verification. Any outstanding code is consumed
+// so it cannot be used after the link, or the other way around.
+func (u *authUsecase) activate(user *domain.User) error {
+	if !user.AwaitingVerification() {
+		return domain.ErrOTPAlreadyVerified
+	}
+
+	if err := u.userRepo.MarkEmailVerified(user.ID); err != nil {
+		return err
+	}
+	_ = u.redisRepo.DeleteOTP(context.Background(), user.Email)
+
+	_ = u.sendWelcome(user)
+	return nil
+}
+
+// RequestEmailChange starts moving the account to a new address. The new
+// address is sent a code, a link or both, as for registration, and the
+// current one is warned. Nothing changes until the new address confirms.
+func (u *authUsecase) RequestEmailChange(userID, newEmail, password string) error {
+	newEmail = strings.TrimSpace(newEmail)
+
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.userRepo.GetPasswordHash(user.ID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPassword(password, hashedPassword) {
+		return domain.ErrInvalidCredentials
+	}
+
+	normalizedEmail := u.emailPolicy.Normalize(newEmail)
+	if normalizedEmail == u.emailPolicy.Normalize(user.Email) {
+		return domain.NewValidationError("email", "new email must differ from the current one")
+	}
+	if err := u.emailPolicy.Check(newEmail); err != nil {
+		return err
+	}
+
+	// Fail early when the address is taken. Confirmation is the real check,
+	// since another account may claim the address in the meantime.
+	if _, err := u.userRepo.GetByNormalizedEmail(normalizedEmail); err == nil {
+		return domain.ErrUserAlreadyExists
+	} else if !errors.Is(err, domain.ErrUserNotFound) {
+		return err
+	}
+
+	if err := u.userRepo.SetPendingEmail(user.ID, newEmail); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	mode := u.config.Registration.VerificationMode
+	data := map[string]interface{}{}
+
+	if mode != config.EmailVerificationLink {
+		otp := utils.GenerateOTP()
+		if err := u.redisRepo.StoreEmailChangeCode(ctx, user.ID, newEmail, otp); err != nil {
+			return err
+		}
+		data["OTP"] = otp
+		data["ExpiresInMinutes"] = otpLifetimeMinutes
+	} else {
+		// A code from an earlier request must not confirm this address
+		_ = u.redisRepo.DeleteEmailChangeCode(ctx, user.ID)
+	}
+
+	if mode != config.EmailVerificationCode {
+		ttl := u.config.Registration.VerificationLinkTTL
+		token, err := utils.GenerateActionToken(utils.PurposeChangeEmail, emailChangeSubject(user.ID, newEmail), ttl)
+		if err != nil {
+			return err
+		}
+		data["ConfirmLink"] = u.config.App.BaseURL + "/api/auth/confirm-email-change?token=" + url.QueryEscape(token)
+		data["LinkExpiresInHours"] = int(ttl.Hours())
+	}
+
+	if err := u.mailer.SendTemplate(ctx, newEmail, user.Locale, email.TemplateEmailChange, data); err != nil {
+		return err
+	}
+
+	return u.sendEmailChangeNotice(user, newEmail)
+}
+
+func (u *authUsecase) sendEmailChangeNotice(user *domain.User, newEmail string) error {
+	token, err := utils.GenerateActionToken(utils.PurposeRevokeSessions, user.ID, revokeSessionsLinkTTL)
+	if err != nil {
+		return err
+	}
+
+	revokeLink := u.config.App.BaseURL + "/api/auth/revoke-sessions?token=" + url.QueryEscape(token)
+	return u.mailer.SendTemplate(context.Background(), user.Email, user.Locale, email.TemplateEmailChangeNotice, map[string]interface{}{
+		"NewEmail":   newEmail,
+		"RevokeLink": revokeLink,
+	})
+}
+
+// ConfirmEmailChange completes an email change with the code sent to the
+// new address
+func (u *authUsecase) ConfirmEmailChange(userID, otp string) (*domain.User, error) {
+	ctx := context.Background()
+
+	pendingEmail, storedOTP, err := u.redisRepo.GetEmailChangeCode(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	if storedOTP != otp {
+		attempts, err := u.redisRepo.IncrementEmailChangeAttempts(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if attempts >= int64(u.config.OTP.MaxAttempts) {
+			_ = u.redisRepo.DeleteEmailChangeCode(ctx, userID)
+			return nil, domain.ErrTooManyOTPAttempts
+		}
+		return nil, domain.ErrInvalidOTP
+	}
+
+	if err := u.changeEmail(userID, pendingEmail); err != nil {
+		return nil, err
+	}
+
+	return u.userRepo.GetByID(userID)
+}
+
+// ConfirmEmailChangeLink completes an email change from the emailed link.
+// The link names the address it was sent to, so a link for an address that
+// has since been replaced by another request no longer works.
+func (u *authUsecase) ConfirmEmailChangeLink(token string) error {
+	subject, err := utils.ParseActionToken(token, utils.PurposeChangeEmail)
+	if err != nil {
+		return err
+	}
+
+	userID, pendingEmail, ok := strings.Cut(subject, ":")
+	if !ok {
+		return domain.ErrInvalidToken
+	}
+
+	return u.changeEmail(userID, pendingEmail)
+}
+
+func (u *authUsecase) changeEmail(userID, pendingEmail string) error {
+	oldEmail, err := u.userRepo.ConfirmEmailChange(userID, pendingEmail, u.emailPolicy.Normalize(pendingEmail))
 	if err != nil {
 		return err
 	}
 
-	return u.userRepo.UpdateActive(user.ID, true)
+	// Codes issued to the old address no longer apply
+	ctx := context.Background()
+	_ = u.redisRepo.DeleteEmailChangeCode(ctx, userID)
+	_ = u.redisRepo.DeleteEmailData(ctx, oldEmail)
+	return nil
+}
+
+func (u *authUsecase) CheckUsernameAvailable(username string) (bool, error) {
+	username, err := domain.NormalizeUsername(username)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := u.userRepo.GetByUsername(username); err == nil {
+		return false, nil
+	} else if !errors.Is(err, domain.ErrUserNotFound) {
+		return false, err
+	}
+	return true, nil
+}
+
+// findUserByEmail looks the address up as entered, then by its canonical
+// form so variants such as a different case still find the account
+func findUserByEmail(userRepo domain.UserRepository, emailPolicy *emailaddress.Policy, address string) (*domain.User, error) {
+	user, err := userRepo.GetByEmail(address)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return userRepo.GetByNormalizedEmail(emailPolicy.Normalize(address))
+	}
+	return user, err
+}
+
+// findUserByEmailIncludingDeleted is findUserByEmail for lookups that may
+// also return soft-deleted accounts
+func findUserByEmailIncludingDeleted(userRepo domain.UserRepository, emailPolicy *emailaddress.Policy, address string) (*domain.User, error) {
+	user, err := userRepo.GetByEmailIncludingDeleted(address)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return userRepo.GetByNormalizedEmailIncludingDeleted(emailPolicy.Normalize(address))
+	}
+	return user, err
+}
+
+// emailChangeSubject binds a confirmation link to both the user and the
+// address it confirms
+func emailChangeSubject(userID, newEmail string) string {
+	return userID + ":" + newEmail
 }
 
 func (u *authUsecase) ResendOTP(email string) error {
-	user, err := u.userRepo.GetByEmail(email)
+	user, err := findUserByEmail(u.userRepo, u.emailPolicy, email)
 	if err != nil {
 		return err
 	}
 
-	if user.IsActive {
+	if !user.AwaitingVerification() {
 		return errors.New("account already verified")
 	}
 
-	otp := utils.GenerateOTP()
-	err = u.redisRepo.StoreOTP(context.Background(), email, otp)
+	return u.sendVerification(user)
+}
+
+func (u *authUsecase) ResetPassword(email, code, newPassword string) error {
+	ctx := context.Background()
+
+	storedCode, err := u.redisRepo.GetPasswordResetCode(ctx, email)
+	if err != nil {
+		return domain.ErrInvalidResetCode
+	}
+
+	if storedCode != code {
+		attempts, err := u.redisRepo.IncrementPasswordResetAttempts(ctx, email)
+		if err != nil {
+			return err
+		}
+		if attempts >= int64(u.config.OTP.MaxAttempts) {
+			_ = u.redisRepo.DeletePasswordResetCode(ctx, email)
+			return domain.ErrTooManyOTPAttempts
+		}
+		return domain.ErrInvalidResetCode
+	}
+
+	user, err := u.userRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
 		return err
 	}
 
-	return utils.SendOTPEmail(email, otp)
+	if err := u.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	// Sessions started with the old password may belong to whoever it leaked to
+	if err := u.tokenIssuer.EndAllSessions(user.ID); err != nil {
+		return err
+	}
+
+	return u.redisRepo.DeletePasswordResetCode(ctx, email)
 }
 
 func (u *authUsecase) GetUserByID(id string) (*domain.User, error) {
@@ -127,20 +665,159 @@ func (u *authUsecase) GetUserByID(id string) (*domain.User, error) {
 	return user, nil
 }
 
-func (u *authUsecase) DeleteUser(id string, permanent bool) error {
-	// First check if user exists and is active
+func (u *authUsecase) DeleteUser(id string) error {
+	// Soft-deleted users are not visible here, so they cannot be deleted twice
 	user, err := u.userRepo.GetByID(id)
 	if err != nil {
 		return err
 	}
 
-	if user.IsDeleted {
-		return domain.ErrUserNotFound
+	if err := u.userRepo.SoftDelete(id, id); err != nil {
+		return err
+	}
+
+	// Let the user undo the deletion until the grace period ends
+	gracePeriod := u.config.Account.DeletionGracePeriod
+	token, err := utils.GenerateActionToken(utils.PurposeRestoreAccount, user.ID, gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	restoreLink := u.config.App.BaseURL + "/api/auth/restore-account?token=" + url.QueryEscape(token)
+	return u.mailer.SendTemplate(context.Background(), user.Email, user.Locale, email.TemplateAccountDeletion, map[string]interface{}{
+		"RestoreLink": restoreLink,
+		"PurgeAt":     time.Now().Add(gracePeriod),
+	})
+}
+
+func (u *authUsecase) RestoreAccount(token string) error {
+	userID, err := utils.ParseActionToken(token, utils.PurposeRestoreAccount)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByIDIncludingDeleted(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsDeleted {
+		return nil
+	}
+
+	return u.restore(user)
+}
+
+// PurgeDeletedAccounts permanently removes accounts whose grace period has ended
+func (u *authUsecase) PurgeDeletedAccounts() (int, error) {
+	const batchSize = 100
+
+	cutoff := time.Now().Add(-u.config.Account.DeletionGracePeriod)
+	purged := 0
+
+	for {
+		users, err := u.userRepo.ListDeletedBefore(cutoff, batchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			if err := u.purge(user); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(users) < batchSize {
+			return purged, nil
+		}
 	}
+}
 
-	if permanent {
-		return u.userRepo.HardDelete(id)
+// restore brings back a soft-deleted account if its grace period has not ended
+func (u *authUsecase) restore(user *domain.User) error {
+	if user.DeletedAt == nil || time.Since(*user.DeletedAt) > u.config.Account.DeletionGracePeriod {
+		return domain.ErrRestoreExpired
 	}
 
-	return u.userRepo.SoftDelete(id)
+	status, err := u.userRepo.Restore(user.ID)
+	if err != nil {
+		return err
+	}
+
+	user.IsDeleted = false
+	user.DeletedAt = nil
+	user.DeletedBy = ""
+	user.StatusBeforeDelete = ""
+	user.Status = status
+	user.IsActive = status == domain.UserStatusActive
+
+	// A suspension may have expired while the account was deleted
+	return u.liftExpiredSuspension(user)
+}
+
+// purge hard deletes the user and scrubs data kept outside the users table.
+// Rows referencing the user are removed by ON DELETE CASCADE.
+func (u *authUsecase) purge(user *domain.User) error {
+	return purgeUser(u.userRepo, u.redisRepo, user)
+}
+
+// purgeUser permanently deletes the account and the short-lived data held
+// for it in Redis
+func purgeUser(userRepo domain.UserRepository, redisRepo *repository.RedisRepository, user *domain.User) error {
+	if err := userRepo.HardDelete(user.ID); err != nil {
+		return err
+	}
+
+	return redisRepo.DeleteUserData(context.Background(), user.ID, user.Email)
+}
+
+// enforceStatus rejects users who may not sign in, lifting suspensions
+// whose expiry has passed along the way
+func (u *authUsecase) enforceStatus(user *domain.User) error {
+	if err := u.liftExpiredSuspension(user); err != nil {
+		return err
+	}
+
+	err := user.CheckStatus(time.Now())
+	if errors.Is(err, domain.ErrSuspendedUser) && user.SuspensionReason != "" {
+		return fmt.Errorf("%w: %s", err, user.SuspensionReason)
+	}
+	return err
+}
+
+func (u *authUsecase) liftExpiredSuspension(user *domain.User) error {
+	if user.Status != domain.UserStatusSuspended || user.EffectiveStatus(time.Now()) != domain.UserStatusActive {
+		return nil
+	}
+
+	if err := u.userRepo.UpdateStatus(user.ID, domain.StatusChange{Status: domain.UserStatusActive}); err != nil {
+		return err
+	}
+	user.Status = domain.UserStatusActive
+	user.SuspensionReason = ""
+	user.SuspendedUntil = nil
+	return nil
+}
+
+func (u *authUsecase) sendWelcome(user *domain.User) error {
+	return u.mailer.SendTemplate(context.Background(), user.Email, user.Locale, email.TemplateWelcome, map[string]interface{}{
+		"Name": user.Name,
+	})
+}
+
+func (u *authUsecase) sendOTP(to, locale, otp string) error {
+	return u.mailer.SendTemplate(context.Background(), to, locale, email.TemplateOTP, map[string]interface{}{
+		"OTP":              otp,
+		"ExpiresInMinutes": otpLifetimeMinutes,
+	})
+}
+
+// emailLocale picks the language for emails about a login: the user's own
+// preference, else the language the client asked for
+func emailLocale(user *domain.User, client domain.ClientInfo) string {
+	if user.Locale != "" {
+		return user.Locale
+	}
+	return client.Locale
 }
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"auth-service/internal/emailaddress"
+	"auth-service/internal/utils"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryUserRepository keeps users in a slice and hides soft-deleted ones
+// the way the Postgres repository does. Methods the tests don't need panic
+// through the nil embedded interface.
+type memoryUserRepository struct {
+	domain.UserRepository
+	users     []*domain.User
+	passwords map[string]string
+}
+
+func newMemoryUserRepository() *memoryUserRepository {
+	return &memoryUserRepository{passwords: map[string]string{}}
+}
+
+func (r *memoryUserRepository) find(match func(*domain.User) bool, includeDeleted bool) (*domain.User, error) {
+	var found *domain.User
+	for _, user := range r.users {
+		if !match(user) || (user.IsDeleted && !includeDeleted) {
+			continue
+		}
+		// Prefer the visible account, as the repository does
+		if found == nil || (found.IsDeleted && !user.IsDeleted) {
+			found = user
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	copied := *found
+	return &copied, nil
+}
+
+func (r *memoryUserRepository) GetByID(id string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.ID == id }, false)
+}
+
+func (r *memoryUserRepository) GetByIDIncludingDeleted(id string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.ID == id }, true)
+}
+
+func (r *memoryUserRepository) GetByEmail(address string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Email == address }, false)
+}
+
+func (r *memoryUserRepository) GetByEmailIncludingDeleted(address string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Email == address }, true)
+}
+
+func (r *memoryUserRepository) GetByNormalizedEmail(normalizedEmail string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.NormalizedEmail == normalizedEmail }, false)
+}
+
+func (r *memoryUserRepository) GetByNormalizedEmailIncludingDeleted(normalizedEmail string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.NormalizedEmail == normalizedEmail }, true)
+}
+
+func (r *memoryUserRepository) GetByUsername(username string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool {
+		return u.Username != "" && strings.EqualFold(u.Username, username)
+	}, false)
+}
+
+func (r *memoryUserRepository) GetPasswordHash(id string) (string, error) {
+	hash, ok := r.passwords[id]
+	if !ok {
+		return "", domain.ErrUserNotFound
+	}
+	return hash, nil
+}
+
+// Create enforces email uniqueness among live accounts only, like the
+// partial unique index
+func (r *memoryUserRepository) Create(user *domain.User) error {
+	if _, err := r.GetByEmail(user.Email); err == nil {
+		return domain.ErrUserAlreadyExists
+	}
+	stored := *user
+	r.users = append(r.users, &stored)
+	r.passwords[user.ID] = user.Password
+	return nil
+}
+
+// addDeleted stores a soft-deleted account with the given password
+func (r *memoryUserRepository) addDeleted(t *testing.T, id, address, password, deletedBy string, deletedAt time.Time) *domain.User {
+	t.Helper()
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	user := &domain.User{
+		ID:                 id,
+		Email:              address,
+		NormalizedEmail:    address,
+		Name:               "Deleted User",
+		Status:             domain.UserStatusDeleted,
+		StatusBeforeDelete: domain.UserStatusActive,
+		IsDeleted:          true,
+		DeletedAt:          &deletedAt,
+		DeletedBy:          deletedBy,
+	}
+	r.users = append(r.users, user)
+	r.passwords[id] = hash
+	return user
+}
+
+func (r *memoryUserRepository) live(address string) []*domain.User {
+	var users []*domain.User
+	for _, user := range r.users {
+		if user.Email == address && !user.IsDeleted {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+type memoryRoleRepository struct {
+	domain.RoleRepository
+	assigned map[string][]string
+}
+
+func (r *memoryRoleRepository) AssignRole(userID, roleName string) error {
+	if r.assigned == nil {
+		r.assigned = map[string][]string{}
+	}
+	r.assigned[userID] = append(r.assigned[userID], roleName)
+	return nil
+}
+
+type memoryLoginHistoryRepository struct {
+	domain.LoginHistoryRepository
+	events []*domain.LoginEvent
+}
+
+func (r *memoryLoginHistoryRepository) Record(event *domain.LoginEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+type authUsecaseFixture struct {
+	usecase *authUsecase
+	users   *memoryUserRepository
+	roles   *memoryRoleRepository
+	history *memoryLoginHistoryRepository
+	sent    *email.MemorySender
+}
+
+// newAuthUsecaseFixture builds an auth usecase on in-memory fakes. Email
+// verification uses links only, so registration does not need Redis.
+func newAuthUsecaseFixture(t *testing.T) *authUsecaseFixture {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	cfg := &config.Config{}
+	cfg.App.BaseURL = "http://localhost:8080"
+	cfg.Registration.VerificationMode = config.EmailVerificationLink
+	cfg.Registration.VerificationLinkTTL = time.Hour
+	cfg.Account.DeletionGracePeriod = 30 * 24 * time.Hour
+
+	renderer, err := email.NewRenderer("", "en")
+	if err != nil {
+		t.Fatalf("failed to load email templates: %v", err)
+	}
+	sent := email.NewMemorySender()
+	mailer := email.NewMailer(sent, renderer, "no-reply@example.com")
+
+	policy, err := emailaddress.NewPolicy(emailaddress.PolicyConfig{})
+	if err != nil {
+		t.Fatalf("failed to build email policy: %v", err)
+	}
+
+	users := newMemoryUserRepository()
+	roles := &memoryRoleRepository{}
+	history := &memoryLoginHistoryRepository{}
+	recorder := NewLoginRecorder(history, nil, mailer, cfg)
+
+	return &authUsecaseFixture{
+		usecase: NewAuthUsecase(users, roles, nil, mailer, nil, nil, recorder, policy, cfg).(*authUsecase),
+		users:   users,
+		roles:   roles,
+		history: history,
+		sent:    sent,
+	}
+}
+
+func TestLoginRejectsSoftDeletedUser(t *testing.T) {
+	const (
+		userID   = "5f0c2b8e-3a43-4d0f-9f3a-1b7c9d2e4a10"
+		adminID  = "a1d3c0de-7b2f-4c55-8e0a-6f9b2c4d1e33"
+		address  = "deleted@example.com"
+		password = "correct-horse"
+	)
+
+	tests := []struct {
+		name      string
+		deletedBy string
+		deletedAt time.Time
+		password  string
+		wantErr   error
+	}{
+		{"deleted by an administrator", adminID, time.Now().Add(-time.Hour), password, domain.ErrInvalidCredentials},
+		{"self-deleted past the grace period", userID, time.Now().Add(-31 * 24 * time.Hour), password, domain.ErrRestoreExpired},
+		{"wrong password", userID, time.Now().Add(-time.Hour), "wrong-password", domain.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newAuthUsecaseFixture(t)
+			fixture.users.addDeleted(t, userID, address, password, tt.deletedBy, tt.deletedAt)
+
+			token, err := fixture.usecase.Login(address, tt.password, domain.ClientInfo{IPAddress: "203.0.113.7"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if token != "" {
+				t.Fatalf("got a token for a deleted account")
+			}
+
+			if len(fixture.history.events) != 1 || fixture.history.events[0].Success {
+				t.Fatalf("got %d login events, want one failure", len(fixture.history.events))
+			}
+
+			stored, _ := fixture.users.GetByIDIncludingDeleted(userID)
+			if !stored.IsDeleted {
+				t.Fatalf("a rejected login restored the account")
+			}
+		})
+	}
+}
+
+func TestGetUserByIDHidesSoftDeletedUser(t *testing.T) {
+	fixture := newAuthUsecaseFixture(t)
+	deleted := fixture.users.addDeleted(t, "0e6a9a0c-1f1b-4d8e-8f43-2f5d0c7b9a21", "gone@example.com", "password1", "", time.Now())
+
+	user, err := fixture.usecase.GetUserByID(deleted.ID)
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("got error %v, want %v", err, domain.ErrUserNotFound)
+	}
+	if user != nil {
+		t.Fatalf("got user %+v for a deleted account", user)
+	}
+}
+
+func TestRegisterReusesEmailOfDeletedUser(t *testing.T) {
+	const address = "again@example.com"
+
+	fixture := newAuthUsecaseFixture(t)
+	deleted := fixture.users.addDeleted(t, "3c9d7e1a-52b4-4f6e-a0c8-9d1e2f3a4b5c", address, "old-password", "", time.Now())
+
+	user := &domain.User{Email: address, Name: "New Owner", Password: "new-password"}
+	if err := fixture.usecase.Register(user); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if user.ID == deleted.ID {
+		t.Fatalf("registration reused the deleted account's ID")
+	}
+	if live := fixture.users.live(address); len(live) != 1 || live[0].ID != user.ID {
+		t.Fatalf("got %d live accounts for %s, want only the new one", len(live), address)
+	}
+	if roles := fixture.roles.assigned[user.ID]; len(roles) != 1 || roles[0] != domain.RoleUser {
+		t.Fatalf("got roles %v for the new account", roles)
+	}
+	if messages := fixture.sent.Messages(); len(messages) != 1 || messages[0].To[0] != address {
+		t.Fatalf("got %d emails, want one verification email to %s", len(messages), address)
+	}
+
+	// A second sign-up with the address now collides with the live account
+	again := &domain.User{Email: address, Name: "Someone Else", Password: "another-password"}
+	if err := fixture.usecase.Register(again); !errors.Is(err, domain.ErrUserAlreadyExists) {
+		t.Fatalf("second Register: got %v, want %v", err, domain.ErrUserAlreadyExists)
+	}
+}
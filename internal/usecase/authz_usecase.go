@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"auth-service/internal/cache"
+	"auth-service/internal/domain"
+	"auth-service/internal/policy"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const decisionKey = "authz:decision:%s:%s"
+
+type authzUsecase struct {
+	engine   *policy.Engine
+	cache    cache.CacheService
+	cacheTTL time.Duration
+}
+
+// NewAuthzUsecase evaluates requests against the engine's policies. A zero
+// cacheTTL disables the decision cache.
+func NewAuthzUsecase(engine *policy.Engine, cache cache.CacheService, cacheTTL time.Duration) domain.AuthzUsecase {
+	return &authzUsecase{
+		engine:   engine,
+		cache:    cache,
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (u *authzUsecase) Authorize(subject *domain.Subject, action string, resource *domain.Resource) (*domain.Decision, error) {
+	action = strings.TrimSpace(action)
+	if action == "" {
+		return nil, domain.NewValidationError("action", "action is required")
+	}
+	if subject == nil || subject.ID == "" {
+		return nil, domain.NewValidationError("subject", "subject id is required")
+	}
+	if resource == nil || resource.Type == "" {
+		return nil, domain.NewValidationError("resource", "resource type is required")
+	}
+
+	input := policy.Input{
+		Subject:  subjectAttributes(subject),
+		Action:   action,
+		Resource: resourceAttributes(resource),
+	}
+
+	if u.cacheTTL <= 0 {
+		return evaluate(u.engine, input), nil
+	}
+
+	key, err := u.decisionKey(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var cached domain.Decision
+	if err := u.cache.Get(ctx, key, &cached); err == nil {
+		cached.Cached = true
+		return &cached, nil
+	}
+
+	decision := evaluate(u.engine, input)
+	_ = u.cache.Set(ctx, key, decision, u.cacheTTL)
+	return decision, nil
+}
+
+// decisionKey hashes the full input together with the policy version, so
+// reloading changed policies never serves stale decisions
+func (u *authzUsecase) decisionKey(input policy.Input) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode authorization request: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return fmt.Sprintf(decisionKey, u.engine.Version(), hex.EncodeToString(digest[:])), nil
+}
+
+func evaluate(engine *policy.Engine, input policy.Input) *domain.Decision {
+	result := engine.Evaluate(input)
+	return &domain.Decision{
+		Allowed: result.Allowed,
+		Rule:    result.Rule,
+	}
+}
+
+// subjectAttributes flattens the subject for policies. Built-in fields win
+// over caller-supplied attributes of the same name.
+func subjectAttributes(subject *domain.Subject) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(subject.Attributes)+6)
+	for key, value := range subject.Attributes {
+		attrs[key] = value
+	}
+
+	attrs["id"] = subject.ID
+	attrs["roles"] = stringList(subject.Roles)
+	attrs["permissions"] = stringList(subject.Permissions)
+	attrs["groups"] = stringList(subject.Groups)
+	if subject.OrgID != "" {
+		attrs["org_id"] = subject.OrgID
+		attrs["org_role"] = subject.OrgRole
+	} else {
+		delete(attrs, "org_id")
+		delete(attrs, "org_role")
+	}
+
+	return attrs
+}
+
+func resourceAttributes(resource *domain.Resource) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(resource.Attributes)+2)
+	for key, value := range resource.Attributes {
+		attrs[key] = value
+	}
+
+	attrs["type"] = resource.Type
+	if resource.ID != "" {
+		attrs["id"] = resource.ID
+	}
+
+	return attrs
+}
+
+func stringList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return list
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Delivery retry schedule. A failed message waits the base delay, doubling
+// with each further failure up to the maximum.
+const (
+	emailRetryBaseDelay = 30 * time.Second
+	emailRetryMaxDelay  = time.Hour
+
+	// emailSendTimeout bounds a single delivery attempt, and must stay
+	// below the lease so a slow send is not picked up twice
+	emailSendTimeout = time.Minute
+	emailLease       = 2 * time.Minute
+)
+
+// EmailQueue is an email.Sender that stores messages for background
+// delivery, so requests never wait on the mail provider. Workers drain it
+// through DeliverDue, which hands messages to the real provider.
+type EmailQueue struct {
+	queueRepo   domain.EmailQueueRepository
+	provider    email.Sender
+	maxAttempts int
+	wake        chan struct{}
+}
+
+func NewEmailQueue(queueRepo domain.EmailQueueRepository, provider email.Sender, maxAttempts int) *EmailQueue {
+	return &EmailQueue{
+		queueRepo:   queueRepo,
+		provider:    provider,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Send queues the message for delivery
+func (q *EmailQueue) Send(_ context.Context, msg email.Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	outbound := &domain.OutboundEmail{
+		Recipients: strings.Join(msg.Recipients(), ", "),
+		Subject:    msg.Subject,
+		Payload:    payload,
+	}
+	if err := q.queueRepo.Enqueue(outbound); err != nil {
+		return err
+	}
+
+	q.notify()
+	return nil
+}
+
+// Wake receives whenever a message becomes due ahead of the next poll
+func (q *EmailQueue) Wake() <-chan struct{} {
+	return q.wake
+}
+
+func (q *EmailQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// DeliverDue claims up to limit due messages and attempts each once. It
+// returns how many were claimed and how many of those failed.
+func (q *EmailQueue) DeliverDue(ctx context.Context, limit int) (int, int, error) {
+	emails, err := q.queueRepo.ClaimDue(limit, emailLease)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	failed := 0
+	for _, outbound := range emails {
+		if err := q.deliver(ctx, outbound); err != nil {
+			failed++
+		}
+	}
+
+	return len(emails), failed, nil
+}
+
+func (q *EmailQueue) deliver(ctx context.Context, outbound *domain.OutboundEmail) error {
+	var msg email.Message
+	if err := json.Unmarshal(outbound.Payload, &msg); err != nil {
+		// Retrying cannot fix a payload that does not decode
+		_ = q.queueRepo.MarkFailed(outbound.ID, err.Error(), time.Now(), true)
+		return err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, emailSendTimeout)
+	defer cancel()
+
+	if err := q.provider.Send(sendCtx, msg); err != nil {
+		dead := outbound.Attempts >= q.maxAttempts
+		_ = q.queueRepo.MarkFailed(outbound.ID, err.Error(), time.Now().Add(emailRetryDelay(outbound.Attempts)), dead)
+		return err
+	}
+
+	return q.queueRepo.MarkSent(outbound.ID)
+}
+
+// emailRetryDelay is the wait after the given number of failed attempts
+func emailRetryDelay(attempts int) time.Duration {
+	delay := emailRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= emailRetryMaxDelay {
+			return emailRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+type emailQueueUsecase struct {
+	queueRepo domain.EmailQueueRepository
+	queue     *EmailQueue
+}
+
+func NewEmailQueueUsecase(queueRepo domain.EmailQueueRepository, queue *EmailQueue) domain.EmailQueueUsecase {
+	return &emailQueueUsecase{
+		queueRepo: queueRepo,
+		queue:     queue,
+	}
+}
+
+func (u *emailQueueUsecase) ListDeadLetters(page, pageSize int) (*domain.OutboundEmailPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	emails, total, err := u.queueRepo.ListByStatus(domain.OutboundEmailDead, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OutboundEmailPage{
+		Emails:   emails,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (u *emailQueueUsecase) GetEmail(id string) (*domain.OutboundEmail, error) {
+	return u.queueRepo.GetByID(id)
+}
+
+// ReplayEmail gives a dead letter a fresh set of delivery attempts
+func (u *emailQueueUsecase) ReplayEmail(id string) error {
+	if err := u.queueRepo.Replay(id); err != nil {
+		return err
+	}
+
+	u.queue.notify()
+	return nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"strings"
+)
+
+type groupUsecase struct {
+	groupRepo domain.GroupRepository
+	userRepo  domain.UserRepository
+}
+
+func NewGroupUsecase(groupRepo domain.GroupRepository, userRepo domain.UserRepository) domain.GroupUsecase {
+	return &groupUsecase{
+		groupRepo: groupRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (u *groupUsecase) CreateGroup(group *domain.Group) error {
+	group.Name = strings.ToLower(strings.TrimSpace(group.Name))
+	if group.Name == "" {
+		return domain.NewValidationError("name", "group name is required")
+	}
+	if strings.ContainsAny(group.Name, " \t") {
+		return domain.NewValidationError("name", "group name must not contain whitespace")
+	}
+
+	return u.groupRepo.Create(group)
+}
+
+func (u *groupUsecase) GetGroup(id string) (*domain.Group, error) {
+	return u.groupRepo.GetByID(id)
+}
+
+func (u *groupUsecase) ListGroups() ([]*domain.Group, error) {
+	return u.groupRepo.List()
+}
+
+func (u *groupUsecase) DeleteGroup(id string) error {
+	return u.groupRepo.Delete(id)
+}
+
+func (u *groupUsecase) AddMember(groupID, userID string) error {
+	if _, err := u.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	return u.groupRepo.AddMember(groupID, userID)
+}
+
+func (u *groupUsecase) RemoveMember(groupID, userID string) error {
+	return u.groupRepo.RemoveMember(groupID, userID)
+}
+
+func (u *groupUsecase) AddChild(parentID, childID string) error {
+	return u.groupRepo.AddChild(parentID, childID)
+}
+
+func (u *groupUsecase) RemoveChild(parentID, childID string) error {
+	return u.groupRepo.RemoveChild(parentID, childID)
+}
+
+func (u *groupUsecase) GetUserGroups(userID string) ([]string, error) {
+	return u.groupRepo.GetUserGroups(userID)
+}
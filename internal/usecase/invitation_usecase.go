@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"auth-service/internal/emailaddress"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type invitationUsecase struct {
+	invitationRepo domain.InvitationRepository
+	orgRepo        domain.OrganizationRepository
+	userRepo       domain.UserRepository
+	roleRepo       domain.RoleRepository
+	mailer         *email.Mailer
+	tokenIssuer    *TokenIssuer
+	recorder       *LoginRecorder
+	emailPolicy    *emailaddress.Policy
+	config         *config.Config
+}
+
+func NewInvitationUsecase(
+	invitationRepo domain.InvitationRepository,
+	orgRepo domain.OrganizationRepository,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	mailer *email.Mailer,
+	tokenIssuer *TokenIssuer,
+	recorder *LoginRecorder,
+	emailPolicy *emailaddress.Policy,
+	cfg *config.Config,
+) domain.InvitationUsecase {
+	return &invitationUsecase{
+		invitationRepo: invitationRepo,
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		mailer:         mailer,
+		tokenIssuer:    tokenIssuer,
+		recorder:       recorder,
+		emailPolicy:    emailPolicy,
+		config:         cfg,
+	}
+}
+
+func (u *invitationUsecase) CreateInvitation(orgID, actorID, email string, role domain.OrgRole) (*domain.Invitation, error) {
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidOrgRole
+	}
+
+	actor, err := u.orgRepo.GetMembership(orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.Role.CanManageMembers() || (role == domain.OrgRoleOwner && actor.Role != domain.OrgRoleOwner) {
+		return nil, domain.ErrForbidden
+	}
+
+	// Invite existing users in their own language
+	locale := ""
+	email = strings.TrimSpace(email)
+	existing, err := findUserByEmail(u.userRepo, u.emailPolicy, email)
+	switch {
+	case err == nil:
+		if _, err := u.orgRepo.GetMembership(orgID, existing.ID); err == nil {
+			return nil, domain.ErrAlreadyMember
+		}
+		locale = existing.Locale
+	case errors.Is(err, domain.ErrUserNotFound):
+		// The invitation would create an account, so it must pass sign-up rules
+		if err := u.emailPolicy.Check(email); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	org, err := u.orgRepo.GetByID(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.invitationRepo.RevokePending(orgID, email); err != nil {
+		return nil, err
+	}
+
+	expiration := u.config.Registration.InvitationExpiration
+	invitation := &domain.Invitation{
+		OrganizationID:   orgID,
+		OrganizationName: org.Name,
+		Email:            email,
+		Role:             role,
+		InvitedBy:        actorID,
+		ExpiresAt:        time.Now().Add(expiration),
+	}
+
+	if err := u.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateActionToken(utils.PurposeInvitation, invitation.ID, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteLink := u.config.App.BaseURL + "/api/invitations/accept?token=" + url.QueryEscape(token)
+	if err := u.sendInvitation(invitation, inviteLink, locale); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (u *invitationUsecase) sendInvitation(invitation *domain.Invitation, inviteLink, locale string) error {
+	return u.mailer.SendTemplate(context.Background(), invitation.Email, locale, email.TemplateInvitation, map[string]interface{}{
+		"OrgName":    invitation.OrganizationName,
+		"InviteLink": inviteLink,
+		"ExpiresAt":  invitation.ExpiresAt,
+	})
+}
+
+func (u *invitationUsecase) ListInvitations(orgID string) ([]*domain.Invitation, error) {
+	return u.invitationRepo.ListByOrganization(orgID)
+}
+
+func (u *invitationUsecase) RevokeInvitation(orgID, id string) error {
+	return u.invitationRepo.Revoke(orgID, id)
+}
+
+// GetInvitation resolves a token to a still-pending invitation
+func (u *invitationUsecase) GetInvitation(token string) (*domain.Invitation, error) {
+	invitationID, err := utils.ParseActionToken(token, utils.PurposeInvitation)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := u.invitationRepo.GetByID(invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.Status(time.Now()) != domain.InvitationPending {
+		return nil, domain.ErrInvitationInvalid
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation joins the invited email to the organization, creating an
+// already active account when needed since the emailed token proves the
+// address. It returns a token scoped to the organization for a new account.
+// An existing account gets no token and has to sign in as usual, since the
+// invitation proves the address but not the password.
+func (u *invitationUsecase) AcceptInvitation(token, name, password string, client domain.ClientInfo) (string, error) {
+	invitation, err := u.GetInvitation(token)
+	if err != nil {
+		return "", err
+	}
+
+	user, accessToken, err := u.accept(invitation, name, password, client)
+	if err != nil {
+		u.recorder.RecordFailure(user, invitation.Email, domain.LoginMethodInvitation, client, err)
+		return "", err
+	}
+
+	if accessToken != "" {
+		u.recorder.RecordSuccess(user, domain.LoginMethodInvitation, client)
+	}
+	return accessToken, nil
+}
+
+// accept returns the invited user, once known, alongside the outcome. The
+// invitation is claimed before anything is created, so a revoked, expired or
+// concurrently accepted invitation leaves no account behind. A failed sign-up
+// releases the claim for another try.
+func (u *invitationUsecase) accept(invitation *domain.Invitation, name, password string, client domain.ClientInfo) (*domain.User, string, error) {
+	user, err := findUserByEmail(u.userRepo, u.emailPolicy, invitation.Email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return user, "", err
+	}
+
+	if user != nil {
+		return user, "", u.acceptExisting(invitation, user)
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return nil, "", domain.NewValidationError("name", "name is required to create an account")
+	}
+	if len(password) < 8 {
+		return nil, "", domain.NewValidationError("password", "password must be at least 8 characters long")
+	}
+	// The policy may have changed since the invitation was sent
+	if err := u.emailPolicy.Check(invitation.Email); err != nil {
+		return nil, "", err
+	}
+
+	if err := u.invitationRepo.Claim(invitation); err != nil {
+		return nil, "", err
+	}
+
+	if user, err = u.createInvitedUser(invitation.Email, name, password, client.Locale); err != nil {
+		_ = u.invitationRepo.Release(invitation.ID)
+		return nil, "", err
+	}
+
+	if err := u.join(invitation, user); err != nil {
+		return user, "", err
+	}
+
+	membership, err := u.orgRepo.GetMembership(invitation.OrganizationID, user.ID)
+	if err != nil {
+		return user, "", err
+	}
+
+	accessToken, err := u.tokenIssuer.StartSession(user, membership, client)
+	return user, accessToken, err
+}
+
+// acceptExisting joins an existing account to the organization. An account
+// still waiting on email verification is activated, since the emailed token
+// proves the address.
+func (u *invitationUsecase) acceptExisting(invitation *domain.Invitation, user *domain.User) error {
+	awaitingVerification := user.AwaitingVerification()
+	if !awaitingVerification {
+		if err := user.CheckStatus(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := u.invitationRepo.Claim(invitation); err != nil {
+		return err
+	}
+
+	if awaitingVerification {
+		if err := u.userRepo.MarkEmailVerified(user.ID); err != nil && !errors.Is(err, domain.ErrOTPAlreadyVerified) {
+			_ = u.invitationRepo.Release(invitation.ID)
+			return err
+		}
+	}
+
+	return u.join(invitation, user)
+}
+
+// join adds the user to the organization of an invitation already claimed
+func (u *invitationUsecase) join(invitation *domain.Invitation, user *domain.User) error {
+	if err := u.orgRepo.AddMember(invitation.OrganizationID, user.ID, invitation.Role); err != nil && !errors.Is(err, domain.ErrAlreadyMember) {
+		return err
+	}
+
+	return nil
+}
+
+func (u *invitationUsecase) createInvitedUser(email, name, password, locale string) (*domain.User, error) {
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:              uuid.New().String(),
+		Email:           email,
+		NormalizedEmail: u.emailPolicy.Normalize(email),
+		Name:            strings.TrimSpace(name),
+		Locale:          locale,
+		Password:        hashedPassword,
+		IsActive:        true,
+		Status:          domain.UserStatusActive,
+		EmailVerifiedAt: &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := u.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	if err := u.roleRepo.AssignRole(user.ID, domain.RoleUser); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"auth-service/internal/risk"
+	"auth-service/internal/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// revokeSessionsLinkTTL is how long the "this wasn't me" link stays valid
+const revokeSessionsLinkTTL = 7 * 24 * time.Hour
+
+// LoginRecorder appends login attempts to the login history and warns users
+// about sign-ins from devices they have not used before. It is shared by
+// every flow that signs a user in.
+type LoginRecorder struct {
+	historyRepo domain.LoginHistoryRepository
+	scorer      *risk.Scorer
+	mailer      *email.Mailer
+	config      *config.Config
+}
+
+// NewLoginRecorder takes a nil scorer when risk scoring is disabled
+func NewLoginRecorder(historyRepo domain.LoginHistoryRepository, scorer *risk.Scorer, mailer *email.Mailer, cfg *config.Config) *LoginRecorder {
+	return &LoginRecorder{
+		historyRepo: historyRepo,
+		scorer:      scorer,
+		mailer:      mailer,
+		config:      cfg,
+	}
+}
+
+// Assess scores a login against the user's previous successful one
+func (r *LoginRecorder) Assess(user *domain.User, client domain.ClientInfo) risk.Assessment {
+	if r.scorer == nil {
+		return risk.Assessment{}
+	}
+
+	var previous *risk.Attempt
+	if last, err := r.historyRepo.LastSuccess(user.ID); err == nil {
+		previous = &risk.Attempt{IPAddress: last.IPAddress, At: last.CreatedAt}
+	}
+
+	return r.scorer.Score(previous, risk.Attempt{IPAddress: client.IPAddress, At: time.Now()})
+}
+
+// RecordFailure stores a failed attempt. The user is nil when the email
+// matched no account.
+func (r *LoginRecorder) RecordFailure(user *domain.User, email, method string, client domain.ClientInfo, cause error) {
+	event := newLoginEvent(user, email, method, client)
+	event.FailureReason = loginFailureReason(cause)
+	_ = r.historyRepo.Record(event)
+
+	if user != nil && errors.Is(cause, domain.ErrSessionLimitReached) {
+		r.sendSessionLimitNotice(user, client, event.CreatedAt)
+	}
+}
+
+// sendSessionLimitNotice gives a user refused by the session limit a way
+// back in when the sessions holding the slots can't be logged out, e.g.
+// because their tokens were lost. The limit is only checked once the
+// credentials are verified, so the notice goes to the account owner.
+func (r *LoginRecorder) sendSessionLimitNotice(user *domain.User, client domain.ClientInfo, attemptedAt time.Time) {
+	token, err := utils.GenerateActionToken(utils.PurposeRevokeSessions, user.ID, revokeSessionsLinkTTL)
+	if err != nil {
+		return
+	}
+
+	revokeLink := r.config.App.BaseURL + "/api/auth/revoke-sessions?token=" + url.QueryEscape(token)
+	_ = r.mailer.SendTemplate(context.Background(), user.Email, emailLocale(user, client), email.TemplateSessionLimit, map[string]interface{}{
+		"MaxSessions": r.config.Sessions.MaxConcurrent,
+		"IPAddress":   client.IPAddress,
+		"UserAgent":   client.UserAgent,
+		"AttemptedAt": attemptedAt,
+		"RevokeLink":  revokeLink,
+	})
+}
+
+// RecordSuccess stores a successful sign-in and emails the user when it
+// came from an unfamiliar device. The first ever sign-in is not reported.
+func (r *LoginRecorder) RecordSuccess(user *domain.User, method string, client domain.ClientInfo) {
+	event := newLoginEvent(user, user.Email, method, client)
+	event.Success = true
+
+	// Look the device up before recording, or this sign-in would count as seen
+	seen, hasHistory, seenErr := r.historyRepo.DeviceSeen(user.ID, event.DeviceFingerprint)
+	if err := r.historyRepo.Record(event); err != nil {
+		return
+	}
+	if seenErr != nil || seen || !hasHistory {
+		return
+	}
+
+	token, err := utils.GenerateActionToken(utils.PurposeRevokeSessions, user.ID, revokeSessionsLinkTTL)
+	if err != nil {
+		return
+	}
+
+	revokeLink := r.config.App.BaseURL + "/api/auth/revoke-sessions?token=" + url.QueryEscape(token)
+	_ = r.mailer.SendTemplate(context.Background(), user.Email, emailLocale(user, client), email.TemplateNewDevice, map[string]interface{}{
+		"IPAddress":  client.IPAddress,
+		"UserAgent":  client.UserAgent,
+		"SignedInAt": event.CreatedAt,
+		"RevokeLink": revokeLink,
+	})
+}
+
+func newLoginEvent(user *domain.User, email, method string, client domain.ClientInfo) *domain.LoginEvent {
+	event := &domain.LoginEvent{
+		Email:             email,
+		Method:            method,
+		IPAddress:         client.IPAddress,
+		UserAgent:         client.UserAgent,
+		DeviceFingerprint: deviceFingerprint(client),
+	}
+	if user != nil {
+		event.UserID = user.ID
+	}
+	return event
+}
+
+// deviceFingerprint identifies the browser or client a login came from. The
+// IP address is left out since it changes as devices move between networks.
+func deviceFingerprint(client domain.ClientInfo) string {
+	sum := sha256.Sum256([]byte(client.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// loginFailureReason reduces an error to a stable reason code for the history
+func loginFailureReason(err error) string {
+	var validationErr *domain.ValidationError
+	var stepUpErr *domain.StepUpRequiredError
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, domain.ErrInactiveUser):
+		return "inactive"
+	case errors.Is(err, domain.ErrSuspendedUser):
+		return "suspended"
+	case errors.Is(err, domain.ErrRestoreExpired):
+		return "deleted"
+	case errors.Is(err, domain.ErrPasswordResetRequired):
+		return "password_reset_required"
+	case errors.Is(err, domain.ErrSessionLimitReached):
+		return "session_limit_reached"
+	case errors.Is(err, domain.ErrInvitationInvalid):
+		return "invitation_invalid"
+	case errors.As(err, &stepUpErr):
+		return "step_up_required"
+	case errors.Is(err, domain.ErrInvalidOTP):
+		return "invalid_otp"
+	case errors.Is(err, domain.ErrTooManyOTPAttempts):
+		return "too_many_otp_attempts"
+	case errors.As(err, &validationErr):
+		return "invalid_request"
+	default:
+		return "error"
+	}
+}
+
+type loginHistoryUsecase struct {
+	historyRepo domain.LoginHistoryRepository
+}
+
+func NewLoginHistoryUsecase(historyRepo domain.LoginHistoryRepository) domain.LoginHistoryUsecase {
+	return &loginHistoryUsecase{
+		historyRepo: historyRepo,
+	}
+}
+
+func (u *loginHistoryUsecase) ListLoginHistory(userID string, page, pageSize int) (*domain.LoginEventPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	events, total, err := u.historyRepo.ListByUser(userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginEventPage{
+		Events:   events,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
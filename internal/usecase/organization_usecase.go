@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"regexp"
+	"strings"
+)
+
+var (
+	slugPattern      = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type organizationUsecase struct {
+	orgRepo     domain.OrganizationRepository
+	userRepo    domain.UserRepository
+	tokenIssuer *TokenIssuer
+}
+
+func NewOrganizationUsecase(
+	orgRepo domain.OrganizationRepository,
+	userRepo domain.UserRepository,
+	tokenIssuer *TokenIssuer,
+) domain.OrganizationUsecase {
+	return &organizationUsecase{
+		orgRepo:     orgRepo,
+		userRepo:    userRepo,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+func (u *organizationUsecase) CreateOrganization(userID string, org *domain.Organization) error {
+	org.Name = strings.TrimSpace(org.Name)
+	if org.Name == "" {
+		return domain.NewValidationError("name", "organization name is required")
+	}
+
+	if org.Slug == "" {
+		org.Slug = strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(org.Name), "-"), "-")
+	}
+	if len(org.Slug) > 100 || !slugPattern.MatchString(org.Slug) {
+		return domain.NewValidationError("slug", "slug may only contain lowercase letters, digits and dashes")
+	}
+
+	return u.orgRepo.Create(org, userID)
+}
+
+func (u *organizationUsecase) GetOrganization(orgID string) (*domain.Organization, error) {
+	return u.orgRepo.GetByID(orgID)
+}
+
+func (u *organizationUsecase) ListMemberships(userID string) ([]*domain.Membership, error) {
+	return u.orgRepo.ListMembershipsByUser(userID)
+}
+
+func (u *organizationUsecase) GetMembership(orgID, userID string) (*domain.Membership, error) {
+	return u.orgRepo.GetMembership(orgID, userID)
+}
+
+func (u *organizationUsecase) ListMembers(orgID string) ([]*domain.Membership, error) {
+	return u.orgRepo.ListMembers(orgID)
+}
+
+func (u *organizationUsecase) AddMember(orgID, actorID, email string, role domain.OrgRole) error {
+	if err := u.authorizeRoleChange(orgID, actorID, role); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	return u.orgRepo.AddMember(orgID, user.ID, role)
+}
+
+func (u *organizationUsecase) UpdateMemberRole(orgID, actorID, userID string, role domain.OrgRole) error {
+	if err := u.authorizeRoleChange(orgID, actorID, role); err != nil {
+		return err
+	}
+
+	target, err := u.orgRepo.GetMembership(orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.authorizeTarget(orgID, actorID, target); err != nil {
+		return err
+	}
+
+	return u.orgRepo.UpdateMemberRole(orgID, userID, role)
+}
+
+// RemoveMember lets managers remove members and anyone leave on their own
+func (u *organizationUsecase) RemoveMember(orgID, actorID, userID string) error {
+	target, err := u.orgRepo.GetMembership(orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if actorID != userID {
+		actor, err := u.orgRepo.GetMembership(orgID, actorID)
+		if err != nil {
+			return err
+		}
+		if !actor.Role.CanManageMembers() {
+			return domain.ErrForbidden
+		}
+	}
+
+	if err := u.authorizeTarget(orgID, actorID, target); err != nil {
+		return err
+	}
+
+	return u.orgRepo.RemoveMember(orgID, userID)
+}
+
+// SwitchOrganization issues a token scoped to one of the user's
+// organizations, within the caller's current session
+func (u *organizationUsecase) SwitchOrganization(userID, orgID, sessionFamily string) (string, error) {
+	membership, err := u.orgRepo.GetMembership(orgID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	return u.tokenIssuer.Continue(user, membership, sessionFamily)
+}
+
+// authorizeRoleChange checks that the actor may grant the role. Only owners
+// can hand out ownership.
+func (u *organizationUsecase) authorizeRoleChange(orgID, actorID string, role domain.OrgRole) error {
+	if !role.IsValid() {
+		return domain.ErrInvalidOrgRole
+	}
+
+	actor, err := u.orgRepo.GetMembership(orgID, actorID)
+	if err != nil {
+		return err
+	}
+
+	if !actor.Role.CanManageMembers() || (role == domain.OrgRoleOwner && actor.Role != domain.OrgRoleOwner) {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeTarget protects owners from non-owners and keeps at least one
+// owner in the organization
+func (u *organizationUsecase) authorizeTarget(orgID, actorID string, target *domain.Membership) error {
+	if target.Role != domain.OrgRoleOwner {
+		return nil
+	}
+
+	if target.UserID != actorID {
+		actor, err := u.orgRepo.GetMembership(orgID, actorID)
+		if err != nil {
+			return err
+		}
+		if actor.Role != domain.OrgRoleOwner {
+			return domain.ErrForbidden
+		}
+	}
+
+	owners, err := u.orgRepo.CountOwners(orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain"
+	"auth-service/internal/email"
+	"auth-service/internal/notify"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+)
+
+// OTPNotifier sends one-time codes to phone numbers. It is shared by phone
+// verification and login step-up, and reports ErrNotificationsUnavailable
+// when no notification provider is configured.
+type OTPNotifier struct {
+	channel  notify.NotificationChannel
+	renderer *email.Renderer
+}
+
+// NewOTPNotifier takes a nil channel when phone notifications are disabled
+func NewOTPNotifier(channel notify.NotificationChannel, renderer *email.Renderer) *OTPNotifier {
+	return &OTPNotifier{
+		channel:  channel,
+		renderer: renderer,
+	}
+}
+
+// Available reports whether codes can be sent to phones at all
+func (n *OTPNotifier) Available() bool {
+	return n.channel != nil
+}
+
+func (n *OTPNotifier) Send(channel, to, locale, otp string) error {
+	if n.channel == nil {
+		return domain.ErrNotificationsUnavailable
+	}
+	if !notify.IsChannel(channel) {
+		return domain.ErrInvalidChannel
+	}
+
+	return n.channel.Send(context.Background(), notify.Notification{
+		Channel: channel,
+		To:      to,
+		Body:    n.renderer.Translate(locale, "phone.otp", otp, otpLifetimeMinutes),
+		Code:    otp,
+		Locale:  locale,
+	})
+}
+
+type phoneUsecase struct {
+	userRepo  domain.UserRepository
+	redisRepo *repository.RedisRepository
+	notifier  *OTPNotifier
+	config    *config.Config
+}
+
+func NewPhoneUsecase(userRepo domain.UserRepository, redisRepo *repository.RedisRepository, notifier *OTPNotifier, cfg *config.Config) domain.PhoneUsecase {
+	return &phoneUsecase{
+		userRepo:  userRepo,
+		redisRepo: redisRepo,
+		notifier:  notifier,
+		config:    cfg,
+	}
+}
+
+// StartPhoneVerification texts or calls a code to the number. Codes are
+// rate limited per user and per number, since each one costs money and can
+// be used to harass the number's owner.
+func (u *phoneUsecase) StartPhoneVerification(userID, phoneNumber, channel string) error {
+	if !u.notifier.Available() {
+		return domain.ErrNotificationsUnavailable
+	}
+	if channel == "" {
+		channel = notify.ChannelSMS
+	}
+	if !notify.IsChannel(channel) {
+		return domain.ErrInvalidChannel
+	}
+
+	phoneNumber, err := domain.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := u.redisRepo.ReservePhoneVerificationSend(ctx, user.ID, phoneNumber, u.config.Notify.VerificationCooldown, u.config.Notify.VerificationDailyLimit); err != nil {
+		return err
+	}
+
+	otp := utils.GenerateOTP()
+	if err := u.redisRepo.StorePhoneVerification(ctx, user.ID, phoneNumber, otp); err != nil {
+		return err
+	}
+
+	return u.notifier.Send(channel, phoneNumber, user.Locale, otp)
+}
+
+func (u *phoneUsecase) ConfirmPhoneVerification(userID, otp string) (*domain.User, error) {
+	ctx := context.Background()
+
+	phoneNumber, storedOTP, err := u.redisRepo.GetPhoneVerification(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	if storedOTP != otp {
+		attempts, err := u.redisRepo.IncrementPhoneVerificationAttempts(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if attempts >= int64(u.config.OTP.MaxAttempts) {
+			_ = u.redisRepo.DeletePhoneVerification(ctx, userID)
+			return nil, domain.ErrTooManyOTPAttempts
+		}
+		return nil, domain.ErrInvalidOTP
+	}
+
+	if err := u.userRepo.SetPhoneNumber(userID, phoneNumber); err != nil {
+		return nil, err
+	}
+	_ = u.redisRepo.DeletePhoneVerification(ctx, userID)
+
+	return u.userRepo.GetByID(userID)
+}
+
+func (u *phoneUsecase) RemovePhoneNumber(userID string) error {
+	_ = u.redisRepo.DeletePhoneVerification(context.Background(), userID)
+	return u.userRepo.SetPhoneNumber(userID, "")
+}
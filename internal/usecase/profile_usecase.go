@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Profile limits
+const (
+	maxNameLength      = 255
+	maxAvatarURLLength = 2048
+	maxAttributesBytes = 16 << 10
+	maxAttributeSchema = 64 << 10
+)
+
+// attributeSchemaURL names the admin schema when compiling it. A fixed URN
+// keeps server paths out of compilation errors.
+const attributeSchemaURL = "urn:auth-service:user-attributes"
+
+// localePattern accepts BCP 47 style tags such as "en" or "pt-BR"
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+type profileUsecase struct {
+	userRepo   domain.UserRepository
+	schemaRepo domain.AttributeSchemaRepository
+}
+
+func NewProfileUsecase(userRepo domain.UserRepository, schemaRepo domain.AttributeSchemaRepository) domain.ProfileUsecase {
+	return &profileUsecase{
+		userRepo:   userRepo,
+		schemaRepo: schemaRepo,
+	}
+}
+
+func (u *profileUsecase) UpdateProfile(userID string, update domain.ProfileUpdate) (*domain.User, error) {
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" || len(name) > maxNameLength {
+			return nil, domain.NewValidationError("name", "name must be between 1 and 255 characters")
+		}
+		user.Name = name
+	}
+
+	if update.Username != nil {
+		username := strings.TrimSpace(*update.Username)
+		if username != "" {
+			if username, err = domain.NormalizeUsername(username); err != nil {
+				return nil, err
+			}
+			if existing, err := u.userRepo.GetByUsername(username); err == nil && existing.ID != user.ID {
+				return nil, domain.ErrUsernameTaken
+			} else if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+				return nil, err
+			}
+		}
+		user.Username = username
+	}
+
+	if update.Locale != nil {
+		locale := strings.ReplaceAll(strings.TrimSpace(*update.Locale), "_", "-")
+		if locale != "" && (len(locale) > 35 || !localePattern.MatchString(locale)) {
+			return nil, domain.NewValidationError("locale", "locale must be a language tag such as en or pt-BR")
+		}
+		user.Locale = locale
+	}
+
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+				return nil, domain.NewValidationError("timezone", "timezone must be an IANA time zone such as Europe/Madrid")
+			}
+		}
+		user.Timezone = timezone
+	}
+
+	if update.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*update.AvatarURL)
+		if avatarURL != "" && !isWebURL(avatarURL) {
+			return nil, domain.NewValidationError("avatarUrl", "avatar URL must be an absolute http or https URL")
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	if update.Attributes != nil {
+		attributes, err := u.mergeAttributes(user.Attributes, update.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		user.Attributes = attributes
+	}
+
+	if err := u.userRepo.UpdateProfile(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// mergeAttributes applies changes to the current attributes, removing keys
+// set to null, and validates the result against the current schema
+func (u *profileUsecase) mergeAttributes(current, changes map[string]interface{}) (map[string]interface{}, error) {
+	attributeSchema, err := u.schemaRepo.GetCurrent()
+	if errors.Is(err, domain.ErrAttributeSchemaNotFound) {
+		return nil, domain.NewValidationError("attributes", "custom attributes are disabled until an administrator defines a schema")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]interface{}, len(current)+len(changes))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) > maxAttributesBytes {
+		return nil, domain.NewValidationError("attributes", "custom attributes must not exceed 16 KB")
+	}
+
+	schema, err := compileAttributeSchema(attributeSchema.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the decoded JSON so numbers and nesting match what is stored
+	var instance interface{}
+	if err := json.Unmarshal(encoded, &instance); err != nil {
+		return nil, err
+	}
+	if err := schema.Validate(instance); err != nil {
+		return nil, domain.NewValidationError("attributes", attributeSchemaViolations(err))
+	}
+
+	return merged, nil
+}
+
+func (u *profileUsecase) GetAttributeSchema() (*domain.AttributeSchema, error) {
+	return u.schemaRepo.GetCurrent()
+}
+
+func (u *profileUsecase) SetAttributeSchema(actorID string, schema json.RawMessage) (*domain.AttributeSchema, error) {
+	if len(schema) > maxAttributeSchema {
+		return nil, domain.NewValidationError("schema", "schema must not exceed 64 KB")
+	}
+	if _, err := compileAttributeSchema(schema); err != nil {
+		return nil, err
+	}
+
+	attributeSchema := &domain.AttributeSchema{
+		Schema:    schema,
+		CreatedBy: actorID,
+	}
+	if err := u.schemaRepo.Create(attributeSchema); err != nil {
+		return nil, err
+	}
+
+	return attributeSchema, nil
+}
+
+// compileAttributeSchema compiles an admin-supplied schema. References to
+// other documents are refused so a schema cannot make the service read
+// local files or fetch URLs.
+func compileAttributeSchema(schema []byte) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+	compiler.LoadURL = func(string) (io.ReadCloser, error) {
+		return nil, errors.New("external schema references are not allowed")
+	}
+
+	if err := compiler.AddResource(attributeSchemaURL, bytes.NewReader(schema)); err != nil {
+		return nil, domain.NewValidationError("schema", domain.ErrInvalidAttributeSchema.Error()+": "+err.Error())
+	}
+
+	compiled, err := compiler.Compile(attributeSchemaURL)
+	if err != nil {
+		return nil, domain.NewValidationError("schema", domain.ErrInvalidAttributeSchema.Error()+": "+err.Error())
+	}
+
+	return compiled, nil
+}
+
+// attributeSchemaViolations lists the innermost failures, which name the
+// offending attribute, instead of the nested chain the validator reports
+func attributeSchemaViolations(err error) string {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+
+	var violations []string
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			location := e.InstanceLocation
+			if location == "" {
+				location = "/"
+			}
+			violations = append(violations, location+": "+e.Message)
+			return
+		}
+		for _, cause := range e.Causes {
+			collect(cause)
+		}
+	}
+	collect(validationErr)
+
+	return strings.Join(violations, "; ")
+}
+
+func isWebURL(raw string) bool {
+	if len(raw) > maxAvatarURLLength {
+		return false
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"strings"
+)
+
+type rbacUsecase struct {
+	roleRepo domain.RoleRepository
+	userRepo domain.UserRepository
+}
+
+func NewRBACUsecase(roleRepo domain.RoleRepository, userRepo domain.UserRepository) domain.RBACUsecase {
+	return &rbacUsecase{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (u *rbacUsecase) CreateRole(role *domain.Role) error {
+	role.Name = strings.ToLower(strings.TrimSpace(role.Name))
+	if role.Name == "" {
+		return domain.NewValidationError("name", "role name is required")
+	}
+
+	return u.roleRepo.CreateRole(role)
+}
+
+func (u *rbacUsecase) ListRoles() ([]*domain.Role, error) {
+	return u.roleRepo.ListRoles()
+}
+
+func (u *rbacUsecase) CreatePermission(permission *domain.Permission) error {
+	permission.Name = strings.ToLower(strings.TrimSpace(permission.Name))
+	if !isValidPermissionName(permission.Name) {
+		return domain.NewValidationError("name", "permission name must look like resource:action")
+	}
+
+	return u.roleRepo.CreatePermission(permission)
+}
+
+func (u *rbacUsecase) ListPermissions() ([]*domain.Permission, error) {
+	return u.roleRepo.ListPermissions()
+}
+
+func (u *rbacUsecase) GrantPermission(roleName, permissionName string) error {
+	return u.roleRepo.AddPermissionToRole(roleName, permissionName)
+}
+
+func (u *rbacUsecase) RevokePermission(roleName, permissionName string) error {
+	return u.roleRepo.RemovePermissionFromRole(roleName, permissionName)
+}
+
+func (u *rbacUsecase) AssignRole(userID, roleName string) error {
+	if _, err := u.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+
+	return u.roleRepo.AssignRole(userID, roleName)
+}
+
+func (u *rbacUsecase) RevokeRole(userID, roleName string) error {
+	return u.roleRepo.RevokeRole(userID, roleName)
+}
+
+func (u *rbacUsecase) GetUserRoles(userID string) ([]string, error) {
+	return u.roleRepo.GetUserRoles(userID)
+}
+
+func (u *rbacUsecase) GetUserPermissions(userID string) ([]string, error) {
+	return u.roleRepo.GetUserPermissions(userID)
+}
+
+func isValidPermissionName(name string) bool {
+	resource, action, found := strings.Cut(name, ":")
+	return found && resource != "" && action != "" && !strings.ContainsAny(name, " \t")
+}
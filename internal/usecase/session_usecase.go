@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+// lastSeenInterval bounds how often request traffic updates last_seen_at
+const lastSeenInterval = time.Minute
+
+type sessionUsecase struct {
+	sessionRepo domain.SessionRepository
+	redisRepo   *repository.RedisRepository
+}
+
+func NewSessionUsecase(sessionRepo domain.SessionRepository, redisRepo *repository.RedisRepository) domain.SessionUsecase {
+	return &sessionUsecase{
+		sessionRepo: sessionRepo,
+		redisRepo:   redisRepo,
+	}
+}
+
+// ListSessions returns the user's active sessions, flagging the one the
+// request was made with
+func (u *sessionUsecase) ListSessions(userID, currentFamily string) ([]*domain.Session, error) {
+	sessions, err := u.sessionRepo.ListActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.TokenFamily == currentFamily
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession ends the session and frees its concurrent login slot
+func (u *sessionUsecase) RevokeSession(userID, id string) error {
+	family, err := u.sessionRepo.Revoke(userID, id)
+	if err != nil {
+		return err
+	}
+
+	return u.redisRepo.ReleaseSessionSlot(context.Background(), userID, family)
+}
+
+// Logout ends the session the request was made with and frees its
+// concurrent login slot
+func (u *sessionUsecase) Logout(userID, family string) error {
+	if err := u.sessionRepo.RevokeByFamily(family); err != nil {
+		return err
+	}
+
+	return u.redisRepo.ReleaseSessionSlot(context.Background(), userID, family)
+}
+
+// CheckRevokeAllSessionsToken reports whether a sign-out-everywhere link
+// can still be used, without using it
+func (u *sessionUsecase) CheckRevokeAllSessionsToken(token string) error {
+	if _, err := utils.ParseActionToken(token, utils.PurposeRevokeSessions); err != nil {
+		return err
+	}
+
+	used, err := u.redisRepo.IsActionTokenUsed(context.Background(), utils.ActionTokenID(token))
+	if err != nil {
+		return err
+	}
+	if used {
+		return domain.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// RevokeAllSessions signs the user out everywhere. It backs the "this
+// wasn't me" link in security emails, so the token is the only proof of
+// identity. Each link works once.
+func (u *sessionUsecase) RevokeAllSessions(token string) error {
+	userID, err := utils.ParseActionToken(token, utils.PurposeRevokeSessions)
+	if err != nil {
+		return err
+	}
+
+	unused, err := u.redisRepo.ConsumeActionToken(context.Background(), utils.ActionTokenID(token), revokeSessionsLinkTTL)
+	if err != nil {
+		return err
+	}
+	if !unused {
+		return domain.ErrInvalidToken
+	}
+
+	families, err := u.sessionRepo.RevokeAllByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		_ = u.redisRepo.ReleaseSessionSlot(context.Background(), userID, family)
+	}
+
+	return nil
+}
+
+// ValidateSession checks that the token's session is still live and
+// records activity on it
+func (u *sessionUsecase) ValidateSession(userID, family string) error {
+	session, err := u.sessionRepo.GetByFamily(family)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return domain.ErrSessionRevoked
+		}
+		return err
+	}
+
+	now := time.Now()
+	if session.UserID != userID || !session.IsActive(now) {
+		if session.RevokedAt != nil {
+			// Tell sessions pushed out by the login limit apart from explicit sign-outs
+			if evicted, _ := u.redisRepo.IsSessionEvicted(context.Background(), family); evicted {
+				return domain.ErrSessionEvicted
+			}
+		}
+		return domain.ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) > lastSeenInterval {
+		_ = u.sessionRepo.TouchLastSeen(family)
+	}
+
+	return nil
+}
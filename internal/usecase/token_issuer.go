@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenIssuer mints access tokens for every usecase that signs users in,
+// so all tokens carry the same authorization claims
+type TokenIssuer struct {
+	roleRepo    domain.RoleRepository
+	groupRepo   domain.GroupRepository
+	sessionRepo domain.SessionRepository
+	redisRepo   *repository.RedisRepository
+	config      *config.Config
+}
+
+func NewTokenIssuer(
+	roleRepo domain.RoleRepository,
+	groupRepo domain.GroupRepository,
+	sessionRepo domain.SessionRepository,
+	redisRepo *repository.RedisRepository,
+	cfg *config.Config,
+) *TokenIssuer {
+	return &TokenIssuer{
+		roleRepo:    roleRepo,
+		groupRepo:   groupRepo,
+		sessionRepo: sessionRepo,
+		redisRepo:   redisRepo,
+		config:      cfg,
+	}
+}
+
+// StartSession records a new login session and returns its first token,
+// scoped to the membership's organization when one is given. Logins past
+// the concurrent session limit are refused or evict the oldest sessions,
+// depending on the configured strategy.
+func (t *TokenIssuer) StartSession(user *domain.User, membership *domain.Membership, client domain.ClientInfo) (string, error) {
+	ctx := context.Background()
+	family := uuid.New().String()
+
+	evicted, ok, err := t.redisRepo.AcquireSessionSlot(
+		ctx,
+		user.ID,
+		family,
+		t.config.Sessions.MaxConcurrent,
+		t.config.Sessions.LimitStrategy == config.SessionLimitEvictOldest,
+		utils.AccessTokenLifetime,
+	)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", domain.ErrSessionLimitReached
+	}
+
+	for _, oldFamily := range evicted {
+		if err := t.evict(ctx, oldFamily); err != nil {
+			return "", err
+		}
+	}
+
+	session := &domain.Session{
+		UserID:      user.ID,
+		TokenFamily: family,
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IPAddress,
+		ExpiresAt:   time.Now().Add(utils.AccessTokenLifetime),
+	}
+	if err := t.sessionRepo.Create(session); err != nil {
+		_ = t.redisRepo.ReleaseSessionSlot(ctx, user.ID, family)
+		return "", err
+	}
+
+	return t.issue(user, membership, family)
+}
+
+// evict revokes a session that lost its slot, flagging it so its tokens
+// are rejected as evicted rather than signed out
+func (t *TokenIssuer) evict(ctx context.Context, family string) error {
+	if err := t.redisRepo.MarkSessionEvicted(ctx, family, utils.AccessTokenLifetime); err != nil {
+		return err
+	}
+
+	return t.sessionRepo.RevokeByFamily(family)
+}
+
+// Continue issues another token within an existing session, e.g. when the
+// user switches organizations
+func (t *TokenIssuer) Continue(user *domain.User, membership *domain.Membership, family string) (string, error) {
+	if err := t.sessionRepo.Extend(family, time.Now().Add(utils.AccessTokenLifetime)); err != nil {
+		return "", err
+	}
+
+	if err := t.redisRepo.RefreshSessionSlot(context.Background(), user.ID, family, utils.AccessTokenLifetime); err != nil {
+		return "", err
+	}
+
+	return t.issue(user, membership, family)
+}
+
+// EndAllSessions revokes every session of the user and frees their
+// concurrent login slots, so tokens already issued stop working
+func (t *TokenIssuer) EndAllSessions(userID string) error {
+	families, err := t.sessionRepo.RevokeAllByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		_ = t.redisRepo.ReleaseSessionSlot(context.Background(), userID, family)
+	}
+
+	return nil
+}
+
+func (t *TokenIssuer) issue(user *domain.User, membership *domain.Membership, family string) (string, error) {
+	roles, err := t.roleRepo.GetUserRoles(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	permissions, err := t.roleRepo.GetUserPermissions(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	groups, err := t.groupRepo.GetUserGroups(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := utils.TokenClaims{
+		Roles:       roles,
+		Permissions: permissions,
+		Groups:      groups,
+		SessionID:   family,
+	}
+	if membership != nil {
+		claims.OrgID = membership.OrganizationID
+		claims.OrgRole = string(membership.Role)
+	}
+
+	return utils.GenerateJWT(user, claims)
+}
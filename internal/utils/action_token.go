@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"auth-service/internal/domain"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Action token purposes. A token minted for one purpose is rejected for any other.
+const (
+	PurposeRestoreAccount = "restore-account"
+	PurposeInvitation     = "invitation"
+	PurposeRevokeSessions = "revoke-sessions"
+	PurposeLoginStepUp    = "login-step-up"
+	PurposeVerifyEmail    = "verify-email"
+	PurposeChangeEmail    = "change-email"
+)
+
+// GenerateActionToken signs a short-lived token for an emailed link, such as
+// account restoration. The subject is usually a user ID.
+func GenerateActionToken(purpose, subject string, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"sub": subject,
+		"pur": purpose,
+		"jti": uuid.New().String(),
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(ttl).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+}
+
+// ActionTokenID identifies a token for single-use links without storing the
+// token itself
+func ActionTokenID(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseActionToken verifies the token and returns its subject
+func ParseActionToken(tokenString, purpose string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !token.Valid {
+		return "", domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["pur"] != purpose {
+		return "", domain.ErrInvalidToken
+	}
+
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return "", domain.ErrInvalidToken
+	}
+
+	return subject, nil
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// Cookie and header names used by browser clients in cookie session mode
+const (
+	AccessTokenCookie = "access_token"
+	CSRFCookie        = "csrf_token"
+	CSRFHeader        = "X-CSRF-Token"
+
+	// AuthSourceCookie marks requests authenticated by the session cookie
+	AuthSourceCookie = "cookie"
+)
+
+// GenerateCSRFToken returns a random token for the double-submit cookie
+func GenerateCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate CSRF token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
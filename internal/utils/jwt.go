@@ -8,13 +8,59 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateJWT(user *domain.User) (string, error) {
+// AccessTokenLifetime is how long an access token stays valid
+const AccessTokenLifetime = 24 * time.Hour
+
+// TokenClaims holds the authorization data embedded in an access token
+type TokenClaims struct {
+	Roles       []string
+	Permissions []string
+	Groups      []string
+	OrgID       string
+	OrgRole     string
+	SessionID   string
+}
+
+func GenerateJWT(user *domain.User, extra TokenClaims) (string, error) {
 	claims := jwt.MapClaims{
-		"id":    user.ID,
-		"email": user.Email,
-		"exp":   time.Now().Add(24 * time.Hour).Unix(),
+		"id":          user.ID,
+		"email":       user.Email,
+		"roles":       nonNil(extra.Roles),
+		"permissions": nonNil(extra.Permissions),
+		"groups":      nonNil(extra.Groups),
+		"sid":         extra.SessionID,
+		"exp":         time.Now().Add(AccessTokenLifetime).Unix(),
+	}
+
+	// Tenant-scoped tokens are issued when switching organizations
+	if extra.OrgID != "" {
+		claims["org_id"] = extra.OrgID
+		claims["org_role"] = extra.OrgRole
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 }
+
+// ClaimStrings reads a string list claim, which decodes as []interface{}
+func ClaimStrings(claims jwt.MapClaims, key string) []string {
+	raw, ok := claims[key].([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
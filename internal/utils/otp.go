@@ -3,23 +3,8 @@ package utils
 import (
 	"fmt"
 	"math/rand"
-	"net/smtp"
-	"os"
 )
 
 func GenerateOTP() string {
 	return fmt.Sprintf("%06d", rand.Intn(1000000))
 }
-
-func SendOTPEmail(email, otp string) error {
-	from := os.Getenv("SMTP_EMAIL")
-	password := os.Getenv("SMTP_PASSWORD")
-	to := []string{email}
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-
-	message := []byte(fmt.Sprintf("Subject: OTP Verification\n\nYour OTP is: %s", otp))
-
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-	return smtp.SendMail(smtpHost+":"+smtpPort, auth, from, to, message)
-}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// AccountPurgeWorker periodically hard deletes accounts whose deletion
+// grace period has ended.
+type AccountPurgeWorker struct {
+	userUsecase domain.UserUsecase
+	interval    time.Duration
+	logger      *log.Logger
+}
+
+func NewAccountPurgeWorker(userUsecase domain.UserUsecase, interval time.Duration) *AccountPurgeWorker {
+	return &AccountPurgeWorker{
+		userUsecase: userUsecase,
+		interval:    interval,
+		logger:      log.New(os.Stdout, "[ACCOUNT PURGE] ", log.LstdFlags),
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *AccountPurgeWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.runOnce()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *AccountPurgeWorker) runOnce() {
+	purged, err := w.userUsecase.PurgeDeletedAccounts()
+	if err != nil {
+		w.logger.Printf("purge failed after %d accounts: %v", purged, err)
+		return
+	}
+
+	if purged > 0 {
+		w.logger.Printf("purged %d accounts", purged)
+	}
+}
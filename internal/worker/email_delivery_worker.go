@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"auth-service/internal/usecase"
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// EmailDeliveryWorker drains the outbound email queue with a pool of
+// goroutines, polling for retries that have come due and waking early
+// when new mail is queued.
+type EmailDeliveryWorker struct {
+	queue     *usecase.EmailQueue
+	workers   int
+	interval  time.Duration
+	batchSize int
+	logger    *log.Logger
+}
+
+func NewEmailDeliveryWorker(queue *usecase.EmailQueue, workers int, interval time.Duration, batchSize int) *EmailDeliveryWorker {
+	return &EmailDeliveryWorker{
+		queue:     queue,
+		workers:   workers,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    log.New(os.Stdout, "[EMAIL DELIVERY] ", log.LstdFlags),
+	}
+}
+
+// Start runs the workers in the background until ctx is cancelled
+func (w *EmailDeliveryWorker) Start(ctx context.Context) {
+	for i := 0; i < w.workers; i++ {
+		go func() {
+			ticker := time.NewTicker(w.interval)
+			defer ticker.Stop()
+
+			for {
+				w.drain(ctx)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				case <-w.queue.Wake():
+				}
+			}
+		}()
+	}
+}
+
+// drain delivers batches until the queue has nothing more due
+func (w *EmailDeliveryWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, failed, err := w.queue.DeliverDue(ctx, w.batchSize)
+		if err != nil {
+			w.logger.Printf("failed to claim emails: %v", err)
+			return
+		}
+
+		if failed > 0 {
+			w.logger.Printf("%d of %d emails failed and will be retried or dead lettered", failed, claimed)
+		}
+		if claimed < w.batchSize {
+			return
+		}
+	}
+}
@@ -4,13 +4,20 @@ import (
 	"auth-service/internal/cache"
 	"auth-service/internal/config"
 	"auth-service/internal/delivery/http/route"
+	"auth-service/internal/email"
+	"auth-service/internal/emailaddress"
+	"auth-service/internal/notify"
+	"auth-service/internal/policy"
 	"auth-service/internal/repository"
+	"auth-service/internal/risk"
 	"auth-service/internal/usecase"
-	"auth-service/internal/utils"
+	"auth-service/internal/worker"
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
@@ -51,20 +58,143 @@ func main() {
 
 	// Initialize repositories
 	userRepo := repository.NewCachedUserRepository(db, cacheService)
+	roleRepo := repository.NewCachedRoleRepository(db, cacheService)
+	orgRepo := repository.NewCachedOrganizationRepository(db, cacheService)
+	invitationRepo := repository.NewInvitationRepository(db)
+	groupRepo := repository.NewCachedGroupRepository(db, cacheService)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	sessionRepo := repository.NewCachedSessionRepository(db, cacheService)
+	loginHistoryRepo := repository.NewLoginHistoryRepository(db)
+	emailQueueRepo := repository.NewEmailQueueRepository(db)
+	attributeSchemaRepo := repository.NewAttributeSchemaRepository(db)
 	redisRepo := repository.NewRedisRepository(redisClient)
 
 	// Initialize email service
-	emailService := utils.NewEmailService()
+	emailRenderer, err := email.NewRenderer(cfg.Email.TemplateDir, cfg.Email.DefaultLocale)
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+	emailProvider, err := email.NewProvider(cfg.Email.Provider, email.ProviderConfig{
+		SMTPHost:     cfg.SMTP.Host,
+		SMTPPort:     cfg.SMTP.Port,
+		SMTPUsername: cfg.SMTP.Email,
+		SMTPPassword: cfg.SMTP.Password,
+		FilePath:     cfg.Email.FilePath,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure email provider: %v", err)
+	}
+	// Mail is queued and delivered by background workers, so requests never
+	// wait on the provider
+	emailQueue := usecase.NewEmailQueue(emailQueueRepo, emailProvider, cfg.Email.MaxAttempts)
+	var emailSender email.Sender = emailQueue
+	var mailbox *email.Mailbox
+	if cfg.App.DevTools {
+		// Catch mail for GET /dev/mailbox as it is queued
+		mailbox = email.NewMailbox(emailQueue)
+		emailSender = mailbox
+	}
+	mailer := email.NewMailer(emailSender, emailRenderer, cfg.Email.From)
 
-	// Initialize usecase
-	authUsecase := usecase.NewAuthUsecase(userRepo, redisRepo, emailService)
+	// Initialize phone notifications, which stay off without a provider
+	var notificationChannel notify.NotificationChannel
+	if cfg.Notify.Provider != "" {
+		notificationChannel, err = notify.NewProvider(cfg.Notify.Provider, notify.ProviderConfig{
+			From:          cfg.Notify.From,
+			WebhookURL:    cfg.Notify.WebhookURL,
+			WebhookSecret: cfg.Notify.WebhookSecret,
+			TwilioSID:     cfg.Notify.TwilioSID,
+			TwilioToken:   cfg.Notify.TwilioToken,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure notification provider: %v", err)
+		}
+	}
+	otpNotifier := usecase.NewOTPNotifier(notificationChannel, emailRenderer)
+
+	// Load login risk data
+	var riskScorer *risk.Scorer
+	if cfg.Risk.Enabled {
+		if riskScorer, err = newRiskScorer(cfg); err != nil {
+			log.Fatalf("Failed to load risk data: %v", err)
+		}
+	}
+
+	// Load the email address rules for sign-ups
+	emailPolicy, err := emailaddress.NewPolicy(emailaddress.PolicyConfig{
+		Rules: emailaddress.Rules{
+			GmailDots: cfg.Registration.NormalizeGmailDots,
+			GmailPlus: cfg.Registration.NormalizeGmailPlus,
+		},
+		AllowedDomains:     cfg.Registration.AllowedDomains,
+		BlockedDomains:     cfg.Registration.BlockedDomains,
+		BlockDisposable:    cfg.Registration.BlockDisposable,
+		DisposableListPath: cfg.Registration.DisposableDomainList,
+	})
+	if err != nil {
+		log.Fatalf("Failed to load email domain lists: %v", err)
+	}
+
+	// Apply the configured rules to stored addresses, which only migrations
+	// and earlier rule settings have normalized so far
+	emailConflicts, err := userRepo.NormalizeEmails(emailPolicy.Normalize)
+	if err != nil {
+		log.Fatalf("Failed to normalize stored email addresses: %v", err)
+	}
+	for _, conflict := range emailConflicts {
+		log.Printf("Warning: user %s (%s) shares the mailbox %s with user %s; merge them as described in scripts/duplicate_emails.sql",
+			conflict.UserID, conflict.Email, conflict.NormalizedEmail, conflict.HeldBy)
+	}
+
+	// Initialize usecases
+	tokenIssuer := usecase.NewTokenIssuer(roleRepo, groupRepo, sessionRepo, redisRepo, cfg)
+	loginRecorder := usecase.NewLoginRecorder(loginHistoryRepo, riskScorer, mailer, cfg)
+	authUsecase := usecase.NewAuthUsecase(userRepo, roleRepo, redisRepo, mailer, otpNotifier, tokenIssuer, loginRecorder, emailPolicy, cfg)
+	rbacUsecase := usecase.NewRBACUsecase(roleRepo, userRepo)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, redisRepo, mailer, tokenIssuer)
+	orgUsecase := usecase.NewOrganizationUsecase(orgRepo, userRepo, tokenIssuer)
+	invitationUsecase := usecase.NewInvitationUsecase(invitationRepo, orgRepo, userRepo, roleRepo, mailer, tokenIssuer, loginRecorder, emailPolicy, cfg)
+	groupUsecase := usecase.NewGroupUsecase(groupRepo, userRepo)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, userRepo, roleRepo, groupRepo, cfg)
+	sessionUsecase := usecase.NewSessionUsecase(sessionRepo, redisRepo)
+	loginHistoryUsecase := usecase.NewLoginHistoryUsecase(loginHistoryRepo)
+	emailQueueUsecase := usecase.NewEmailQueueUsecase(emailQueueRepo, emailQueue)
+	phoneUsecase := usecase.NewPhoneUsecase(userRepo, redisRepo, otpNotifier, cfg)
+	profileUsecase := usecase.NewProfileUsecase(userRepo, attributeSchemaRepo)
+
+	// Load authorization policies
+	policyEngine, err := policy.LoadDir(cfg.Authz.PolicyDir)
+	if err != nil {
+		log.Fatalf("Failed to load policies: %v", err)
+	}
+	authzUsecase := usecase.NewAuthzUsecase(policyEngine, cacheService, cfg.Authz.DecisionCacheTTL)
+
+	// Start background workers
+	worker.NewAccountPurgeWorker(authUsecase, cfg.Account.PurgeInterval).Start(context.Background())
+	worker.NewEmailDeliveryWorker(emailQueue, cfg.Email.Workers, cfg.Email.PollInterval, cfg.Email.BatchSize).Start(context.Background())
 
 	// Initialize Gin router
 	gin.SetMode(cfg.App.GinMode)
 	router := gin.Default()
 
 	// Setup routes
-	route.SetupRoutes(router, authUsecase)
+	route.SetupRoutes(router, cfg, redisClient, route.Usecases{
+		Auth:         authUsecase,
+		RBAC:         rbacUsecase,
+		Admin:        adminUsecase,
+		Organization: orgUsecase,
+		Invitation:   invitationUsecase,
+		Group:        groupUsecase,
+		Authz:        authzUsecase,
+		APIKey:       apiKeyUsecase,
+		Session:      sessionUsecase,
+		LoginHistory: loginHistoryUsecase,
+		EmailQueue:   emailQueueUsecase,
+		Phone:        phoneUsecase,
+		Profile:      profileUsecase,
+		Emails:       emailRenderer,
+		Mailbox:      mailbox,
+	})
 
 	// Start server
 	log.Printf("Server starting on port %s in %s mode", cfg.App.Port, os.Getenv("APP_ENV"))
@@ -72,3 +202,25 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// newRiskScorer opens the configured GeoIP database and IP reputation list.
+// Either may be left unset.
+func newRiskScorer(cfg *config.Config) (*risk.Scorer, error) {
+	var geo *risk.GeoIP
+	if cfg.Risk.GeoIPDatabase != "" {
+		var err error
+		if geo, err = risk.OpenGeoIP(cfg.Risk.GeoIPDatabase); err != nil {
+			return nil, err
+		}
+	}
+
+	var reputation *risk.ReputationList
+	if cfg.Risk.IPReputationList != "" {
+		var err error
+		if reputation, err = risk.LoadReputationList(cfg.Risk.IPReputationList); err != nil {
+			return nil, err
+		}
+	}
+
+	return risk.NewScorer(geo, reputation, cfg.Risk.MaxTravelSpeed), nil
+}
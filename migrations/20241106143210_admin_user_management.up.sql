@@ -0,0 +1,5 @@
+-- Track administrator-forced password resets
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Bring status in line with the is_active flag for existing accounts
+UPDATE users SET status = 'active' WHERE is_active AND status = 'inactive';
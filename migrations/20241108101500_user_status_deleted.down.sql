@@ -0,0 +1,11 @@
+-- Postgres cannot drop enum values, so rebuild the type without 'deleted'
+UPDATE users SET status = 'inactive' WHERE status = 'deleted';
+
+DROP INDEX IF EXISTS idx_users_status;
+ALTER TABLE users ALTER COLUMN status DROP DEFAULT;
+ALTER TYPE user_status RENAME TO user_status_old;
+CREATE TYPE user_status AS ENUM ('active', 'inactive', 'suspended');
+ALTER TABLE users ALTER COLUMN status TYPE user_status USING status::text::user_status;
+ALTER TABLE users ALTER COLUMN status SET DEFAULT 'inactive';
+DROP TYPE user_status_old;
+CREATE INDEX idx_users_status ON users(status) WHERE NOT is_deleted;
@@ -0,0 +1,3 @@
+-- Adding an enum value must be committed before it can be used,
+-- so this lives in its own migration
+ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'deleted';
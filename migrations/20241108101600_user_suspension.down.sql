@@ -0,0 +1,6 @@
+-- Restore nullable status
+ALTER TABLE users ALTER COLUMN status DROP NOT NULL;
+
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_until;
+ALTER TABLE users DROP COLUMN IF EXISTS suspension_reason;
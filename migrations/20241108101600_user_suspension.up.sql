@@ -0,0 +1,9 @@
+-- Add suspension details
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMP WITH TIME ZONE;
+
+-- Soft-deleted accounts carry the deleted status
+UPDATE users SET status = 'deleted', is_active = FALSE WHERE is_deleted;
+
+-- Status is now the source of truth
+ALTER TABLE users ALTER COLUMN status SET NOT NULL;
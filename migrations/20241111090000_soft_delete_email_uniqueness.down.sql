@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_users_email_active;
+
+-- Restore global email uniqueness (fails if a deleted email was re-registered)
+CREATE INDEX idx_users_email ON users(email) WHERE NOT is_deleted;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
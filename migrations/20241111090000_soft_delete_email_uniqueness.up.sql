@@ -0,0 +1,8 @@
+-- Only visible accounts need unique emails, so a deleted account's
+-- email can be registered again
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_email_active ON users(email) WHERE NOT is_deleted;
+
+-- Support lookups of deleted accounts for restore and purge
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE is_deleted;
@@ -0,0 +1,10 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_memberships_updated_at ON memberships;
+DROP TRIGGER IF EXISTS update_organizations_updated_at ON organizations;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_memberships_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
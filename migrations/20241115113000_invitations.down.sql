@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_invitations_pending_email;
+DROP INDEX IF EXISTS idx_invitations_organization_id;
+
+-- Drop invitations table
+DROP TABLE IF EXISTS invitations;
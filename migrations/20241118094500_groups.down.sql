@@ -0,0 +1,14 @@
+-- Drop seeded permissions
+DELETE FROM permissions WHERE name IN ('groups:read', 'groups:write');
+
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_groups_updated_at ON groups;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_group_children_child_id;
+DROP INDEX IF EXISTS idx_group_members_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS group_children;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,2 @@
+-- Drop seeded permission
+DELETE FROM permissions WHERE name = 'authz:check';
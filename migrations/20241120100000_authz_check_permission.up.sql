@@ -0,0 +1,11 @@
+-- Seed the permission for checking decisions on behalf of other subjects
+INSERT INTO permissions (name, description) VALUES
+    ('authz:check', 'Evaluate authorization policies for any subject')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name = 'authz:check'
+ON CONFLICT DO NOTHING;
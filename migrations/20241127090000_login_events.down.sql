@@ -0,0 +1,10 @@
+-- Drop trigger and function
+DROP TRIGGER IF EXISTS login_events_append_only ON login_events;
+DROP FUNCTION IF EXISTS prevent_login_event_update();
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_login_events_user_device;
+DROP INDEX IF EXISTS idx_login_events_user_created;
+
+-- Drop table
+DROP TABLE IF EXISTS login_events;
@@ -0,0 +1,33 @@
+-- Create login_events table, an append-only record of every login attempt.
+-- user_id is empty for attempts against unknown emails.
+CREATE TABLE IF NOT EXISTS login_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    device_fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_login_events_user_created ON login_events(user_id, created_at DESC);
+CREATE INDEX idx_login_events_user_device ON login_events(user_id, device_fingerprint)
+    WHERE success;
+
+-- Reject updates so history cannot be rewritten. Deletes stay possible so
+-- purging an account removes its history.
+CREATE OR REPLACE FUNCTION prevent_login_event_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'login_events is append-only';
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE TRIGGER login_events_append_only
+    BEFORE UPDATE ON login_events
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_login_event_update();
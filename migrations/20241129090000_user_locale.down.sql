@@ -0,0 +1,2 @@
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,2 @@
+-- Track each user's preferred language for emails
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
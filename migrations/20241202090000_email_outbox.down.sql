@@ -0,0 +1,15 @@
+-- Drop seeded permissions
+DELETE FROM permissions WHERE name IN ('emails:read', 'emails:write');
+
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_email_outbox_updated_at ON email_outbox;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_email_outbox_dead;
+DROP INDEX IF EXISTS idx_email_outbox_due;
+
+-- Drop table
+DROP TABLE IF EXISTS email_outbox;
+
+-- Drop enum
+DROP TYPE IF EXISTS email_status;
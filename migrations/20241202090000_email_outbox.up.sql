@@ -0,0 +1,42 @@
+-- Create email delivery status enum
+CREATE TYPE email_status AS ENUM ('pending', 'sending', 'sent', 'dead');
+
+-- Create email outbox. Messages wait here until a worker delivers them, and
+-- those that run out of retries stay behind as dead letters.
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipients TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    payload JSONB,
+    status email_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_email_outbox_due ON email_outbox(next_attempt_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX idx_email_outbox_dead ON email_outbox(updated_at DESC) WHERE status = 'dead';
+
+-- Create update trigger for updated_at
+CREATE TRIGGER update_email_outbox_updated_at
+    BEFORE UPDATE ON email_outbox
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed email queue permissions for administrators
+INSERT INTO permissions (name, description) VALUES
+    ('emails:read', 'Inspect queued and failed emails'),
+    ('emails:write', 'Replay failed emails')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('emails:read', 'emails:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_users_phone_number;
+
+-- Drop columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS phone_verified_at,
+    DROP COLUMN IF EXISTS phone_number;
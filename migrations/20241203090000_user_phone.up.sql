@@ -0,0 +1,7 @@
+-- Add a verified phone number for OTP delivery over SMS, WhatsApp or voice
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS phone_number VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- A phone number belongs to at most one live account
+CREATE UNIQUE INDEX idx_users_phone_number ON users(phone_number) WHERE phone_number <> '' AND NOT is_deleted;
@@ -0,0 +1,2 @@
+-- Hold a requested email address until the user confirms it
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255) NOT NULL DEFAULT '';
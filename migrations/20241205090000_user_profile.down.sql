@@ -0,0 +1,11 @@
+-- Drop seeded permissions
+DELETE FROM permissions WHERE name IN ('attributes:read', 'attributes:write');
+
+-- Drop tables
+DROP TABLE IF EXISTS user_attribute_schemas;
+
+-- Drop columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS attributes,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,27 @@
+-- Add profile fields and custom attributes
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Create attribute schema history. The newest version validates custom
+-- attributes on every profile update.
+CREATE TABLE IF NOT EXISTS user_attribute_schemas (
+    version SERIAL PRIMARY KEY,
+    schema JSONB NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Seed attribute schema permissions for administrators
+INSERT INTO permissions (name, description) VALUES
+    ('attributes:read', 'Read the custom user attribute schema'),
+    ('attributes:write', 'Change the custom user attribute schema')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('attributes:read', 'attributes:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,32 @@
+-- Canonical form of each email address, so variants of one mailbox cannot
+-- register separately. How Gmail addresses are folded is configurable, so
+-- existing rows only get what every rule set agrees on here, the address
+-- lowercased. The service applies the configured rules on startup.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS normalized_email VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Live accounts whose addresses differ only in case would break the unique
+-- index below. Name them instead; scripts/duplicate_emails.sql finds every
+-- such group and shows how to merge them.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(address, ', ') INTO duplicates
+    FROM (
+        SELECT lower(btrim(email)) AS address
+        FROM users
+        WHERE NOT is_deleted
+        GROUP BY 1
+        HAVING count(*) > 1
+        ORDER BY 1
+        LIMIT 20
+    ) shared;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'live accounts share these email addresses: %. Merge them with scripts/duplicate_emails.sql, then migrate again', duplicates;
+    END IF;
+END $$;
+
+UPDATE users SET normalized_email = lower(btrim(email));
+
+CREATE UNIQUE INDEX idx_users_normalized_email ON users(normalized_email) WHERE normalized_email <> '' AND NOT is_deleted;
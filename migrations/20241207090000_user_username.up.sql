@@ -0,0 +1,5 @@
+-- Optional handle users can sign in with instead of their email address
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(32) NOT NULL DEFAULT '';
+
+-- Usernames are unique among live accounts regardless of case
+CREATE UNIQUE INDEX idx_users_username ON users(lower(username)) WHERE username <> '' AND NOT is_deleted;
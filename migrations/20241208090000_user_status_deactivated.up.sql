@@ -0,0 +1,5 @@
+-- Accounts switched off by an administrator get their own status, so they
+-- cannot be mistaken for accounts still waiting on email verification.
+-- Adding an enum value must be committed before it can be used, so the
+-- backfill lives in the next migration.
+ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'deactivated';
@@ -0,0 +1,2 @@
+-- Revert deactivated accounts to the shared inactive status
+UPDATE users SET status = 'inactive' WHERE status = 'deactivated';
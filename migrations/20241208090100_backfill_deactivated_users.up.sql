@@ -0,0 +1,5 @@
+-- Inactive accounts that have signed in before were verified, so an
+-- administrator must have deactivated them
+UPDATE users SET status = 'deactivated'
+WHERE status = 'inactive'
+  AND EXISTS (SELECT 1 FROM login_events WHERE login_events.user_id = users.id AND login_events.success);
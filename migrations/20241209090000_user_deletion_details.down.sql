@@ -0,0 +1,4 @@
+-- Drop columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS status_before_delete;
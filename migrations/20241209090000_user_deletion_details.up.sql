@@ -0,0 +1,7 @@
+-- Remember who deleted an account and the status it had, so only the
+-- owner's own deletions can be undone by signing in and a restore cannot
+-- lift a suspension or skip email verification. Accounts deleted before
+-- this change can still be restored from the emailed link.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS status_before_delete user_status;
@@ -0,0 +1,2 @@
+-- Drop column
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,23 @@
+-- Record when each account proved its email address, so verification is
+-- tracked directly rather than inferred from the account's status
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Accounts that ever left the inactive status were verified, as were
+-- inactive accounts that have signed in or been changed since sign-up,
+-- since only verification, deactivation or a signed-in user updates the
+-- row. The exact time is unknown, so the sign-up time stands in for it.
+UPDATE users SET email_verified_at = created_at
+WHERE email_verified_at IS NULL
+  AND (
+    COALESCE(status_before_delete, status) <> 'inactive'
+    OR updated_at > created_at
+    OR EXISTS (SELECT 1 FROM login_events WHERE login_events.user_id = users.id AND login_events.success)
+  );
+
+-- Verified accounts still marked inactive were deactivated by an
+-- administrator before deactivation had its own status
+UPDATE users SET status = 'deactivated'
+WHERE status = 'inactive' AND email_verified_at IS NOT NULL;
+
+UPDATE users SET status_before_delete = 'deactivated'
+WHERE status_before_delete = 'inactive' AND email_verified_at IS NOT NULL;
@@ -0,0 +1,44 @@
+-- Finds live accounts that share a mailbox, which block the unique index on
+-- users.normalized_email. Run with psql, e.g.
+--
+--   psql "$DATABASE_URL" -f scripts/duplicate_emails.sql
+--
+-- It lists addresses that differ only in case, which stop the
+-- 20241206090000_user_normalized_email migration. Gmail variants that only
+-- collide under EMAIL_NORMALIZE_GMAIL_DOTS or EMAIL_NORMALIZE_GMAIL_PLUS are
+-- logged by the service at startup instead, with the IDs of both accounts,
+-- since the rules live in its configuration.
+--
+-- To merge a group, keep one account, usually the one signed in to most
+-- recently, and soft-delete the others so their data is purged after the
+-- deletion grace period:
+--
+--   UPDATE users
+--   SET is_deleted = true,
+--       is_active = false,
+--       status_before_delete = status,
+--       status = 'deleted',
+--       deleted_at = CURRENT_TIMESTAMP,
+--       updated_at = CURRENT_TIMESTAMP
+--   WHERE id IN ('<duplicate id>', ...) AND NOT is_deleted;
+--
+-- Move organization memberships, groups or roles the kept account should
+-- inherit before purging. Then migrate again, or restart the service.
+
+-- Addresses shared ignoring case
+SELECT lower(btrim(u.email)) AS address,
+       u.id,
+       u.email,
+       u.status,
+       u.created_at,
+       (SELECT max(l.created_at) FROM login_events l WHERE l.user_id = u.id AND l.success) AS last_login_at
+FROM users u
+WHERE NOT u.is_deleted
+  AND lower(btrim(u.email)) IN (
+      SELECT lower(btrim(email))
+      FROM users
+      WHERE NOT is_deleted
+      GROUP BY 1
+      HAVING count(*) > 1
+  )
+ORDER BY address, last_login_at DESC NULLS LAST;